@@ -0,0 +1,124 @@
+package gl_utils
+
+import (
+	"math"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// AdaptiveGrid a world space grid which changes the distance between its lines according to the camera zoom, so that
+// the lines are never closer than a minimum amount of pixels on screen. Every 'base' minor lines there is a major one
+type AdaptiveGrid struct {
+	base            int
+	minPixelSpacing float32
+	spacing         float32
+	cameraSize      mgl32.Vec2
+	minorLines      *Primitive2D
+	majorLines      *Primitive2D
+	minorColor      Color
+	majorColor      Color
+}
+
+// NewAdaptiveGrid creates a grid whose spacing is a power of base (e.g. 10 or 2). minPixelSpacing is the minimum
+// distance, in pixels, between two minor lines
+func NewAdaptiveGrid(base int, minPixelSpacing float32) *AdaptiveGrid {
+	if base < 2 {
+		base = 10
+	}
+	if minPixelSpacing <= 0 {
+		minPixelSpacing = 8
+	}
+	g := &AdaptiveGrid{
+		base:            base,
+		minPixelSpacing: minPixelSpacing,
+		minorColor:      Color{0.3, 0.3, 0.3, 1},
+		majorColor:      Color{0.6, 0.6, 0.6, 1},
+	}
+	g.minorLines = newLinesPrimitive()
+	g.majorLines = newLinesPrimitive()
+	return g
+}
+
+// SetColors sets the colors of the minor and major lines
+func (g *AdaptiveGrid) SetColors(minor Color, major Color) {
+	g.minorColor = minor
+	g.majorColor = major
+}
+
+// Spacing returns the distance, in world units, between two minor lines chosen for the last drawn frame
+func (g *AdaptiveGrid) Spacing() float32 {
+	return g.spacing
+}
+
+// Draw draws the grid covering the area visible by the camera
+func (g *AdaptiveGrid) Draw(camera *Camera2D) {
+	spacing := g.spacingForZoom(camera.Zoom())
+	cameraSize := mgl32.Vec2{camera.Width(), camera.Height()}
+	if spacing != g.spacing || cameraSize != g.cameraSize {
+		g.spacing = spacing
+		g.cameraSize = cameraSize
+		g.rebuildGeometry()
+	}
+
+	// The geometry is periodic, moving it by multiples of the major spacing keeps the lines in place
+	majorSpacing := g.spacing * float32(g.base)
+	min, max := camera.visibleArea()
+	center := min.Add(max).Mul(0.5)
+	position := mgl32.Vec3{
+		float32(math.Floor(float64(center.X()/majorSpacing))) * majorSpacing,
+		float32(math.Floor(float64(center.Y()/majorSpacing))) * majorSpacing,
+		0,
+	}
+
+	projection := camera.ProjectionMatrix()
+	g.minorLines.SetPosition(position)
+	g.minorLines.SetColor(g.minorColor)
+	g.minorLines.Draw(projection)
+	g.majorLines.SetPosition(position)
+	g.majorLines.SetColor(g.majorColor)
+	g.majorLines.Draw(projection)
+}
+
+func (g *AdaptiveGrid) spacingForZoom(zoom float32) float32 {
+	minSpacing := float64(g.minPixelSpacing / zoom)
+	exponent := math.Ceil(math.Log(minSpacing) / math.Log(float64(g.base)))
+	return float32(math.Pow(float64(g.base), exponent))
+}
+
+func (g *AdaptiveGrid) rebuildGeometry() {
+	// The geometry covers the area visible at the smallest zoom which still uses this spacing, plus one major cell
+	numHalfColumns := int(math.Ceil(float64(g.cameraSize.X()/2/g.minPixelSpacing))) + g.base
+	numHalfRows := int(math.Ceil(float64(g.cameraSize.Y()/2/g.minPixelSpacing))) + g.base
+	numHalfColumns += g.base - numHalfColumns%g.base
+	numHalfRows += g.base - numHalfRows%g.base
+	halfWidth := float32(numHalfColumns) * g.spacing
+	halfHeight := float32(numHalfRows) * g.spacing
+
+	minor := make([]float32, 0)
+	major := make([]float32, 0)
+	for i := -numHalfColumns; i <= numHalfColumns; i++ {
+		x := float32(i) * g.spacing
+		if i%g.base == 0 {
+			major = append(major, x, -halfHeight, x, halfHeight)
+		} else {
+			minor = append(minor, x, -halfHeight, x, halfHeight)
+		}
+	}
+	for i := -numHalfRows; i <= numHalfRows; i++ {
+		y := float32(i) * g.spacing
+		if i%g.base == 0 {
+			major = append(major, -halfWidth, y, halfWidth, y)
+		} else {
+			minor = append(minor, -halfWidth, y, halfWidth, y)
+		}
+	}
+	g.minorLines.SetVertices(minor)
+	g.majorLines.SetVertices(major)
+}
+
+func newLinesPrimitive() *Primitive2D {
+	p := newPrimitive2D(mgl32.Vec3{}, mgl32.Vec2{1, 1}, SharedShaderProgram(VertexShaderBase, "", FragmentShaderSolidColor))
+	p.arrayMode = gl.LINES
+	return p
+}
@@ -0,0 +1,143 @@
+package gl_utils
+
+import "fmt"
+
+// AnimationMode defines what happens when an animation reaches its last frame
+type AnimationMode int
+
+// Animation modes supported
+const (
+	// AnimationLoop restarts from the first frame
+	AnimationLoop AnimationMode = iota
+	// AnimationPingPong plays the frames backward and forward
+	AnimationPingPong
+	// AnimationOnce stops on the last frame and calls OnComplete
+	AnimationOnce
+)
+
+// Animation an ordered list of frames, each one shown for a specific duration
+type Animation struct {
+	frames    []int
+	durations []float32
+	mode      AnimationMode
+	current   int
+	direction int
+	elapsed   float32
+	finished  bool
+	primitive *Primitive2D
+	sheet     *SpriteSheet
+	// OnComplete is called when an AnimationOnce animation reaches its end
+	OnComplete func()
+}
+
+// NewAnimation creates an animation from a list of frame indices. Durations are in seconds, either one per frame or
+// a single value used for all the frames
+func NewAnimation(frames []int, durations []float32, mode AnimationMode) *Animation {
+	if len(frames) == 0 {
+		fmt.Println("Error: an animation needs at least one frame")
+		return nil
+	}
+	if len(durations) == 1 {
+		d := durations[0]
+		durations = make([]float32, len(frames))
+		for i := range durations {
+			durations[i] = d
+		}
+	}
+	if len(durations) != len(frames) {
+		fmt.Println("Error: the number of durations must match the number of frames")
+		return nil
+	}
+	for _, d := range durations {
+		if d <= 0 {
+			fmt.Println("Error: frame durations must be > 0")
+			return nil
+		}
+	}
+
+	return &Animation{
+		frames:    frames,
+		durations: durations,
+		mode:      mode,
+		direction: 1,
+	}
+}
+
+// Bind attaches the animation to a primitive, the UVs of the primitive are updated every time the frame changes
+func (a *Animation) Bind(primitive *Primitive2D, sheet *SpriteSheet) {
+	a.primitive = primitive
+	a.sheet = sheet
+	a.applyFrame()
+}
+
+// Update advances the animation by dt seconds
+func (a *Animation) Update(dt float32) {
+	if a.finished {
+		return
+	}
+	a.elapsed += dt
+	changed := false
+	for !a.finished && a.elapsed >= a.durations[a.current] {
+		a.elapsed -= a.durations[a.current]
+		changed = a.advance() || changed
+	}
+	if changed {
+		a.applyFrame()
+	}
+}
+
+// CurrentFrame returns the index (in the sprite sheet) of the frame currently shown
+func (a *Animation) CurrentFrame() int {
+	return a.frames[a.current]
+}
+
+// Finished returns true when an AnimationOnce animation reached its last frame
+func (a *Animation) Finished() bool {
+	return a.finished
+}
+
+// Reset restarts the animation from the first frame
+func (a *Animation) Reset() {
+	a.current = 0
+	a.direction = 1
+	a.elapsed = 0
+	a.finished = false
+	a.applyFrame()
+}
+
+// advance moves to the next frame, it returns true if the frame changed
+func (a *Animation) advance() bool {
+	numFrames := len(a.frames)
+	switch a.mode {
+	case AnimationLoop:
+		a.current = (a.current + 1) % numFrames
+	case AnimationPingPong:
+		if numFrames == 1 {
+			return false
+		}
+		next := a.current + a.direction
+		if next < 0 || next >= numFrames {
+			a.direction = -a.direction
+			next = a.current + a.direction
+		}
+		a.current = next
+	case AnimationOnce:
+		if a.current == numFrames-1 {
+			a.finished = true
+			a.elapsed = 0
+			if a.OnComplete != nil {
+				a.OnComplete()
+			}
+			return false
+		}
+		a.current++
+	}
+	return true
+}
+
+func (a *Animation) applyFrame() {
+	if a.primitive == nil || a.sheet == nil {
+		return
+	}
+	a.sheet.ApplyFrame(a.primitive, a.CurrentFrame())
+}
@@ -0,0 +1,206 @@
+package gl_utils
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Glyph the position of a character inside the font texture and how to place it, in pixels
+type Glyph struct {
+	X, Y          int
+	Width, Height int
+	XOffset       int
+	YOffset       int
+	XAdvance      int
+}
+
+// BitmapFont a font whose characters are packed into a single texture, described by a BMFont text file
+type BitmapFont struct {
+	texture    *Texture
+	lineHeight int
+	base       int
+	scaleW     int
+	scaleH     int
+	glyphs     map[rune]*Glyph
+	kerning    map[[2]rune]int
+}
+
+// NewBitmapFontFromFile loads a font in the BMFont text format (.fnt). The texture of the first page is loaded from
+// the same directory of the font file
+func NewBitmapFontFromFile(filePath string) (*BitmapFont, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	font, pageFile, err := parseBitmapFont(file)
+	if err != nil {
+		return nil, err
+	}
+	if pageFile == "" {
+		return nil, fmt.Errorf("the font '%s' doesn't reference any texture", filePath)
+	}
+	font.texture = NewTextureFromFile(filepath.Join(filepath.Dir(filePath), pageFile))
+	if font.texture == nil {
+		return nil, fmt.Errorf("cannot load the texture of the font '%s'", filePath)
+	}
+	return font, nil
+}
+
+// NewBitmapFont creates a font reading the BMFont text description from reader. The texture must contain the first
+// (and only) page of the font
+func NewBitmapFont(reader io.Reader, texture *Texture) (*BitmapFont, error) {
+	font, _, err := parseBitmapFont(reader)
+	if err != nil {
+		return nil, err
+	}
+	font.texture = texture
+	return font, nil
+}
+
+// Texture returns the texture containing the characters
+func (f *BitmapFont) Texture() *Texture {
+	return f.texture
+}
+
+// LineHeight returns the distance in pixels between two lines of text
+func (f *BitmapFont) LineHeight() int {
+	return f.lineHeight
+}
+
+// Base returns the distance in pixels from the top of a line to the baseline of the characters
+func (f *BitmapFont) Base() int {
+	return f.base
+}
+
+// Glyph returns the glyph of a character, nil if the font doesn't contain it
+func (f *BitmapFont) Glyph(char rune) *Glyph {
+	return f.glyphs[char]
+}
+
+// Kerning returns the adjustment, in pixels, of the horizontal distance between two consecutive characters
+func (f *BitmapFont) Kerning(first rune, second rune) int {
+	return f.kerning[[2]rune{first, second}]
+}
+
+// parseBitmapFont reads a BMFont text description, returning the font and the file name of its first page
+func parseBitmapFont(reader io.Reader) (*BitmapFont, string, error) {
+	font := &BitmapFont{
+		glyphs:  make(map[rune]*Glyph),
+		kerning: make(map[[2]rune]int),
+	}
+	pageFile := ""
+
+	scanner := bufio.NewScanner(reader)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		tag, attributes := parseBitmapFontLine(scanner.Text())
+		var err error
+		switch tag {
+		case "common":
+			font.lineHeight, err = bitmapFontAttribute(attributes, "lineHeight", err)
+			font.base, err = bitmapFontAttribute(attributes, "base", err)
+			font.scaleW, err = bitmapFontAttribute(attributes, "scaleW", err)
+			font.scaleH, err = bitmapFontAttribute(attributes, "scaleH", err)
+			if err == nil && (font.scaleW <= 0 || font.scaleH <= 0) {
+				err = fmt.Errorf("invalid texture size %dx%d", font.scaleW, font.scaleH)
+			}
+		case "page":
+			if attributes["id"] == "0" {
+				pageFile = attributes["file"]
+			}
+		case "char":
+			var id int
+			glyph := &Glyph{}
+			id, err = bitmapFontAttribute(attributes, "id", err)
+			glyph.X, err = bitmapFontAttribute(attributes, "x", err)
+			glyph.Y, err = bitmapFontAttribute(attributes, "y", err)
+			glyph.Width, err = bitmapFontAttribute(attributes, "width", err)
+			glyph.Height, err = bitmapFontAttribute(attributes, "height", err)
+			glyph.XOffset, err = bitmapFontAttribute(attributes, "xoffset", err)
+			glyph.YOffset, err = bitmapFontAttribute(attributes, "yoffset", err)
+			glyph.XAdvance, err = bitmapFontAttribute(attributes, "xadvance", err)
+			font.glyphs[rune(id)] = glyph
+		case "kerning":
+			var first, second, amount int
+			first, err = bitmapFontAttribute(attributes, "first", err)
+			second, err = bitmapFontAttribute(attributes, "second", err)
+			amount, err = bitmapFontAttribute(attributes, "amount", err)
+			font.kerning[[2]rune{rune(first), rune(second)}] = amount
+		}
+		if err != nil {
+			return nil, "", fmt.Errorf("line %d: %s", lineNumber, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, "", err
+	}
+	if font.scaleW == 0 {
+		return nil, "", fmt.Errorf("the 'common' block is missing")
+	}
+	return font, pageFile, nil
+}
+
+// parseBitmapFontLine splits a line like 'page id=0 file="font.png"' into its tag and its attributes
+func parseBitmapFontLine(line string) (string, map[string]string) {
+	attributes := make(map[string]string)
+	line = strings.TrimSpace(line)
+	tagEnd := strings.IndexAny(line, " \t")
+	if tagEnd < 0 {
+		return line, attributes
+	}
+	tag := line[:tagEnd]
+	rest := line[tagEnd:]
+	for {
+		rest = strings.TrimLeft(rest, " \t")
+		equal := strings.IndexByte(rest, '=')
+		if equal < 0 {
+			break
+		}
+		key := rest[:equal]
+		rest = rest[equal+1:]
+		var value string
+		if strings.HasPrefix(rest, "\"") {
+			// Quoted values can contain spaces
+			end := strings.IndexByte(rest[1:], '"')
+			if end < 0 {
+				value, rest = rest[1:], ""
+			} else {
+				value, rest = rest[1:end+1], rest[end+2:]
+			}
+		} else {
+			end := strings.IndexAny(rest, " \t")
+			if end < 0 {
+				value, rest = rest, ""
+			} else {
+				value, rest = rest[:end], rest[end:]
+			}
+		}
+		attributes[key] = value
+	}
+	return tag, attributes
+}
+
+// bitmapFontAttribute converts an integer attribute. It does nothing if a previous conversion failed, so that a
+// sequence of conversions can be checked only once
+func bitmapFontAttribute(attributes map[string]string, key string, err error) (int, error) {
+	if err != nil {
+		return 0, err
+	}
+	value, found := attributes[key]
+	if !found {
+		return 0, fmt.Errorf("attribute '%s' missing", key)
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("attribute '%s': %s", key, err)
+	}
+	return n, nil
+}
@@ -0,0 +1,83 @@
+package gl_utils
+
+import (
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// BlendMode how the colors of a primitive are combined with the ones already in the framebuffer
+type BlendMode int
+
+// Blend modes supported
+const (
+	// BlendDefault leaves blending as it's currently set in OpenGL
+	BlendDefault BlendMode = iota
+	// BlendNone overwrites the framebuffer
+	BlendNone
+	// BlendAlpha is the usual transparency, for textures with straight alpha
+	BlendAlpha
+	// BlendPremultiplied is the transparency for textures whose colors are premultiplied by alpha
+	BlendPremultiplied
+	// BlendAdditive adds the colors, weighted by alpha, to the framebuffer (e.g. glows, fire)
+	BlendAdditive
+)
+
+// blendState the OpenGL blending state to restore after a primitive has been drawn
+type blendState struct {
+	changed  bool
+	enabled  bool
+	srcRGB   int32
+	dstRGB   int32
+	srcAlpha int32
+	dstAlpha int32
+}
+
+// BlendMode returns the blend mode used to draw the primitive
+func (p *Primitive2D) BlendMode() BlendMode {
+	return p.blendMode
+}
+
+// SetBlendMode sets the blend mode used to draw the primitive. With BlendDefault the current OpenGL state is used,
+// the other modes are applied only while the primitive is drawn
+func (p *Primitive2D) SetBlendMode(mode BlendMode) {
+	p.blendMode = mode
+}
+
+// applyBlendMode sets the OpenGL blending state for the primitive and returns the previous one
+func (p *Primitive2D) applyBlendMode() blendState {
+	if p.blendMode == BlendDefault {
+		return blendState{}
+	}
+	previous := blendState{changed: true, enabled: glc.IsEnabled(gl.BLEND)}
+	glc.GetIntegerv(gl.BLEND_SRC_RGB, &previous.srcRGB)
+	glc.GetIntegerv(gl.BLEND_DST_RGB, &previous.dstRGB)
+	glc.GetIntegerv(gl.BLEND_SRC_ALPHA, &previous.srcAlpha)
+	glc.GetIntegerv(gl.BLEND_DST_ALPHA, &previous.dstAlpha)
+
+	switch p.blendMode {
+	case BlendNone:
+		glc.Disable(gl.BLEND)
+	case BlendAlpha:
+		glc.Enable(gl.BLEND)
+		glc.BlendFuncSeparate(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA, gl.ONE, gl.ONE_MINUS_SRC_ALPHA)
+	case BlendPremultiplied:
+		glc.Enable(gl.BLEND)
+		glc.BlendFunc(gl.ONE, gl.ONE_MINUS_SRC_ALPHA)
+	case BlendAdditive:
+		glc.Enable(gl.BLEND)
+		glc.BlendFunc(gl.SRC_ALPHA, gl.ONE)
+	}
+	return previous
+}
+
+// restoreBlendState restores the state returned by applyBlendMode
+func restoreBlendState(state blendState) {
+	if !state.changed {
+		return
+	}
+	if state.enabled {
+		glc.Enable(gl.BLEND)
+	} else {
+		glc.Disable(gl.BLEND)
+	}
+	glc.BlendFuncSeparate(uint32(state.srcRGB), uint32(state.dstRGB), uint32(state.srcAlpha), uint32(state.dstAlpha))
+}
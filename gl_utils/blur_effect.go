@@ -0,0 +1,175 @@
+package gl_utils
+
+import (
+	"errors"
+	"math"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// MaxBlurRadius is the largest radius, in pixels, supported by BlurEffect
+const MaxBlurRadius = 31
+
+// BlurEffect a separable gaussian blur, rendering alternately horizontal and vertical passes into two render targets
+type BlurEffect struct {
+	horizontal *RenderTarget
+	vertical   *RenderTarget
+	quad       *Primitive2D
+	material   *Material
+	projection mgl32.Mat4
+	radius     int
+	sigma      float32
+	passes     int
+	weights    []float32
+}
+
+// NewBlurEffect creates a blur producing textures of width x height pixels. The default radius is 4 pixels, with a
+// sigma of half the radius, and a single pass
+func NewBlurEffect(width int, height int) (*BlurEffect, error) {
+	horizontal, err := NewRenderTarget(width, height)
+	if err != nil {
+		return nil, err
+	}
+	vertical, err := NewRenderTarget(width, height)
+	if err != nil {
+		horizontal.Release()
+		return nil, err
+	}
+	// The quad and the material share the same program
+	shader := SharedShaderProgram(VertexShaderBase, "", FragmentShaderBlur)
+	b := &BlurEffect{
+		horizontal: horizontal,
+		vertical:   vertical,
+		quad:       NewQuadPrimitiveExt(mgl32.Vec3{}, mgl32.Vec2{1, 1}, shader, nil, nil),
+		material:   NewMaterial(shader),
+		projection: mgl32.Ortho(0, 1, 0, 1, -1, 1),
+		passes:     1,
+	}
+	b.quad.SetMaterial(b.material)
+	b.SetRadius(4, 2)
+	return b, nil
+}
+
+// SetRadius sets the number of pixels sampled on each side of a pixel (up to MaxBlurRadius) and the standard
+// deviation of the gaussian curve used to weigh them
+func (b *BlurEffect) SetRadius(radius int, sigma float32) error {
+	if radius < 1 || radius > MaxBlurRadius {
+		return errors.New("the radius must be between 1 and MaxBlurRadius")
+	}
+	if sigma <= 0 {
+		return errors.New("sigma must be > 0")
+	}
+	b.radius = radius
+	b.sigma = sigma
+
+	// Weights of the samples from the center outwards, normalized so that the total is 1
+	b.weights = make([]float32, radius+1)
+	var total float32
+	for i := range b.weights {
+		b.weights[i] = float32(math.Exp(-float64(i*i) / float64(2*sigma*sigma)))
+		total += b.weights[i]
+		if i > 0 {
+			total += b.weights[i]
+		}
+	}
+	for i := range b.weights {
+		b.weights[i] /= total
+	}
+	return nil
+}
+
+// Radius returns the radius of the blur, in pixels
+func (b *BlurEffect) Radius() int {
+	return b.radius
+}
+
+// Sigma returns the standard deviation of the gaussian curve
+func (b *BlurEffect) Sigma() float32 {
+	return b.sigma
+}
+
+// SetPasses sets how many times the blur is applied, each pass widens the blur
+func (b *BlurEffect) SetPasses(passes int) {
+	if passes < 1 {
+		passes = 1
+	}
+	b.passes = passes
+}
+
+// Passes returns how many times the blur is applied
+func (b *BlurEffect) Passes() int {
+	return b.passes
+}
+
+// Apply blurs the source texture and returns the blurred texture. The returned texture belongs to the effect and is
+// overwritten by the next call
+func (b *BlurEffect) Apply(source *Texture) *Texture {
+	// The render targets are never cleared, every pixel has to be overwritten by each pass
+	blendEnabled := glc.IsEnabled(gl.BLEND)
+	glc.Disable(gl.BLEND)
+	depthTestEnabled := glc.IsEnabled(gl.DEPTH_TEST)
+	glc.Disable(gl.DEPTH_TEST)
+
+	shader := b.material.Shader()
+	glc.UseProgram(shader.ID())
+	shader.SetUniformFloatArray("weights", b.weights)
+	b.material.SetUniform("radius", int32(b.radius))
+
+	texture := source
+	for i := 0; i < b.passes; i++ {
+		b.blurPass(texture, b.horizontal, mgl32.Vec2{1 / float32(texture.width), 0})
+		texture = b.horizontal.Texture()
+		b.blurPass(texture, b.vertical, mgl32.Vec2{0, 1 / float32(texture.height)})
+		texture = b.vertical.Texture()
+	}
+
+	if blendEnabled {
+		glc.Enable(gl.BLEND)
+	}
+	if depthTestEnabled {
+		glc.Enable(gl.DEPTH_TEST)
+	}
+	return texture
+}
+
+// blurPass blurs the source into the target along the direction, which is the size of a texel of the source
+func (b *BlurEffect) blurPass(source *Texture, target *RenderTarget, direction mgl32.Vec2) {
+	b.material.SetTexture("tex", source)
+	b.material.SetUniform("direction", direction)
+	b.quad.DrawTo(target, &b.projection)
+}
+
+// Release deletes the render targets and the geometry of the effect. Its shader program is shared, it stays in the
+// cache
+func (b *BlurEffect) Release() {
+	b.horizontal.Release()
+	b.vertical.Release()
+	b.quad.Release()
+}
+
+const (
+	// FragmentShaderBlur blurs the texture along 'direction' (the size of a texel in the direction of the blur),
+	// sampling 'radius' texels on each side weighted by 'weights' (from the center outwards)
+	FragmentShaderBlur = `
+        #version 410 core
+
+        in vec2 uv_out;
+        out vec4 color;
+
+        uniform sampler2D tex;
+        uniform vec2 direction;
+        uniform int radius;
+        uniform float weights[32];
+
+        void main() {
+            vec4 sum = texture(tex, uv_out) * weights[0];
+            for (int i = 1; i <= radius; i++) {
+                vec2 offset = direction * float(i);
+                sum += texture(tex, uv_out + offset) * weights[i];
+                sum += texture(tex, uv_out - offset) * weights[i];
+            }
+            color = sum;
+        }
+        ` + "\x00"
+)
@@ -0,0 +1,34 @@
+package gl_utils
+
+import (
+	"testing"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+func TestBlurEffectApply(t *testing.T) {
+	r := useRecordingGL(t)
+	blur, err := NewBlurEffect(16, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	source, err := NewTextureFromPixels(16, 16, gl.RGBA, make([]uint8, 16*16*4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	glc.Enable(gl.BLEND)
+	r.reset()
+
+	blurred := blur.Apply(source)
+
+	// A horizontal and a vertical pass
+	if calls := drawCalls(r); len(calls) != 2 {
+		t.Errorf("draw calls %v, expected 2", calls)
+	}
+	if blurred != blur.vertical.Texture() {
+		t.Error("the texture returned isn't the one of the last pass")
+	}
+	if !r.enabled[gl.BLEND] || r.enabled[gl.DEPTH_TEST] {
+		t.Errorf("blending %v and depth test %v after Apply, expected the previous state", r.enabled[gl.BLEND], r.enabled[gl.DEPTH_TEST])
+	}
+}
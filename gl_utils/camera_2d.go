@@ -1,43 +1,65 @@
 package gl_utils
 
 import (
-	"github.com/go-gl/mathgl/mgl32"
+	"errors"
+	"fmt"
 	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
 )
 
 // Camera2D a Camera based on an orthogonal projection
 type Camera2D struct {
-	x                  float32
-	y                  float32
-	width              float32
-	halfWidth          float32
-	height             float32
-	halfHeight         float32
-	zoom               float32
-	minZoom            float32
-	maxZoom            float32
-	centered           bool
-	flipVertical       bool
-	near               float32
-	far                float32
-	projectionMatrix   mgl32.Mat4
-	inverseMatrix      mgl32.Mat4
-	matrixDirty        bool
-}
-
-// NewCamera2D sets up an orthogonal projection camera
+	x                float32
+	y                float32
+	width            float32
+	halfWidth        float32
+	height           float32
+	halfHeight       float32
+	zoom             float32
+	minZoom          float32
+	maxZoom          float32
+	centered         bool
+	flipVertical     bool
+	near             float32
+	far              float32
+	projectionMatrix mgl32.Mat4
+	inverseMatrix    mgl32.Mat4
+	matrixDirty      bool
+	panning          bool
+	panLast          mgl32.Vec2
+	panDelta         mgl32.Vec2
+	panVelocity      mgl32.Vec2
+	panFriction      float32
+	pixelSnap        bool
+	contentScale     mgl32.Vec2
+	viewportOrigin   mgl32.Vec2
+	viewportSize     mgl32.Vec2
+	zoomTarget       float32
+	zoomSmoothing    float32
+	zoomAnimating    bool
+	zoomAnchored     bool
+	zoomAnchor       mgl32.Vec2
+}
+
+// NewCamera2D sets up an orthogonal projection camera. The zoom is validated and clamped like with SetZoom, an invalid
+// zoom is replaced by 1
 func NewCamera2D(width int, height int, zoom float32) *Camera2D {
 	c := &Camera2D{
-		width:      float32(width),
-		halfWidth:  float32(width) / 2,
-		height:     float32(height),
-		halfHeight: float32(height) / 2,
-		zoom:       zoom,
-		minZoom:    0.01,
-		maxZoom:    20,
+		width:        float32(width),
+		halfWidth:    float32(width) / 2,
+		height:       float32(height),
+		halfHeight:   float32(height) / 2,
+		zoom:         1,
+		minZoom:      0.01,
+		maxZoom:      20,
+		panFriction:  5,
+		contentScale: mgl32.Vec2{1, 1},
+		viewportSize: mgl32.Vec2{float32(width), float32(height)},
 	}
 	c.far = -2
 	c.near = 2
+	c.SetZoom(zoom)
 	c.matrixDirty = true
 	c.rebuildMatrix()
 
@@ -53,6 +75,13 @@ func (c *Camera2D) ProjectionMatrix() *mgl32.Mat4 {
 	return &c.projectionMatrix
 }
 
+// InverseProjectionMatrix returns the inverse of the projection matrix, transforming normalized device coordinates
+// back to world coordinates
+func (c *Camera2D) InverseProjectionMatrix() *mgl32.Mat4 {
+	c.rebuildMatrix()
+	return &c.inverseMatrix
+}
+
 // SetPosition sets the current position of the camera. If the camera is centered, the center will be moving
 func (c *Camera2D) SetPosition(x float32, y float32) {
 	c.x = x
@@ -60,6 +89,11 @@ func (c *Camera2D) SetPosition(x float32, y float32) {
 	c.matrixDirty = true
 }
 
+// Position returns the current position of the camera
+func (c *Camera2D) Position() mgl32.Vec2 {
+	return mgl32.Vec2{c.x, c.y}
+}
+
 // Translate move the camera position by the specified amount
 func (c *Camera2D) Translate(x float32, y float32) {
 	if c.flipVertical {
@@ -73,26 +107,95 @@ func (c *Camera2D) Translate(x float32, y float32) {
 // Zoom returns the current zoom level
 func (c *Camera2D) Zoom() float32 { return c.zoom }
 
-// SetZoom sets the zoom factor
+// SetZoom sets the zoom factor, clamped to the zoom range. Values that aren't finite and strictly positive are
+// ignored, since they would make the projection degenerate
 func (c *Camera2D) SetZoom(zoom float32) {
+	if !validZoom(zoom) {
+		fmt.Printf("Error: invalid zoom %v\n", zoom)
+		return
+	}
 	zoom = mgl32.Clamp(zoom, c.minZoom, c.maxZoom)
 	c.zoom = zoom
 	c.matrixDirty = true
 }
 
+// ZoomAt changes the zoom keeping the world point under the screen position passed (in window coordinates) fixed,
+// e.g. to zoom towards the mouse pointer
+func (c *Camera2D) ZoomAt(screen mgl32.Vec2, zoom float32) {
+	before := c.ScreenToWorld(screen)
+	c.SetZoom(zoom)
+	after := c.ScreenToWorld(screen)
+	c.x += before.X() - after.X()
+	c.y += before.Y() - after.Y()
+	c.matrixDirty = true
+}
+
+// ZoomTo starts a smooth transition of the zoom towards target, clamped to the zoom range, advanced by Update.
+// Smoothing is the speed of the transition: each second the zoom covers about 1-e^(-smoothing) of the remaining
+// distance, so higher values get there sooner
+func (c *Camera2D) ZoomTo(target float32, smoothing float32) {
+	c.zoomTarget = mgl32.Clamp(target, c.minZoom, c.maxZoom)
+	c.zoomSmoothing = smoothing
+	c.zoomAnimating = true
+	c.zoomAnchored = false
+}
+
+// ZoomToAt is like ZoomTo, keeping the world point under the screen position passed fixed during the transition
+// (see ZoomAt)
+func (c *Camera2D) ZoomToAt(screen mgl32.Vec2, target float32, smoothing float32) {
+	c.ZoomTo(target, smoothing)
+	c.zoomAnchored = true
+	c.zoomAnchor = screen
+}
+
+// StopZoom stops the transition started by ZoomTo, leaving the zoom where it is
+func (c *Camera2D) StopZoom() {
+	c.zoomAnimating = false
+}
+
 // MinZoom returns the minimum zoom level allowed
 func (c *Camera2D) MinZoom() float32 { return c.minZoom }
 
 // MaxZoom returns the maximum zoom level allowed
 func (c *Camera2D) MaxZoom() float32 { return c.maxZoom }
 
-// SetZoomRange sets the minimum and maximum zoom factors allowed
-func (c *Camera2D) SetZoomRange(minZoom float32, maxZoom float32) {
+// SetZoomRange sets the minimum and maximum zoom factors allowed. Both must be finite and strictly positive
+func (c *Camera2D) SetZoomRange(minZoom float32, maxZoom float32) error {
+	if !validZoom(minZoom) || !validZoom(maxZoom) {
+		return errors.New("the zoom range must be finite and > 0")
+	}
+	if minZoom > maxZoom {
+		return errors.New("the minimum zoom must be <= the maximum zoom")
+	}
 	c.minZoom = minZoom
 	c.maxZoom = maxZoom
 	if c.zoom > c.maxZoom || c.zoom < c.minZoom {
 		c.SetZoom(c.zoom)
 	}
+	return nil
+}
+
+// validZoom returns whether the zoom is a finite number > 0
+func validZoom(zoom float32) bool {
+	return zoom > 0 && !math.IsInf(float64(zoom), 0)
+}
+
+// DepthRange returns the near and far clipping planes of the camera
+func (c *Camera2D) DepthRange() (near float32, far float32) {
+	return c.near, c.far
+}
+
+// SetDepthRange sets the near and far clipping planes. Primitives are visible when their Z is between -near and -far.
+// By default near is 2 and far is -2 (note near > far), making visible the Z values between -2 and 2
+func (c *Camera2D) SetDepthRange(near float32, far float32) error {
+	if near == far {
+		return errors.New("near and far must be different")
+	}
+	c.near = near
+	c.far = far
+	c.matrixDirty = true
+	c.rebuildMatrix()
+	return nil
 }
 
 // SetCentered sets the center of the camera to the center of the screen
@@ -107,20 +210,186 @@ func (c *Camera2D) SetFlipVertical(flip bool) {
 	c.matrixDirty = true
 }
 
-// SetVisibleArea configures the camera to make the specified area completely visible, position and zoom are changed accordingly
-func (c *Camera2D) SetVisibleArea(x1 float32, y1 float32, x2 float32, y2 float32) {
-	width := math.Abs(float64(x2 - x1))
-	height := math.Abs(float64(y2 - y1))
-	zoom := float32(math.Min(float64(c.width)/width, float64(c.height)/height))
-	c.SetZoom(zoom)
+// ContentScale returns the ratio between framebuffer pixels and window coordinates
+func (c *Camera2D) ContentScale() mgl32.Vec2 {
+	return c.contentScale
+}
+
+// SetContentScale sets the ratio between framebuffer pixels and window coordinates (e.g. 2 on Retina displays).
+// The screen coordinates passed to and returned by the camera are in window coordinates, like the mouse position
+// reported by the OS, while the camera size is in framebuffer pixels
+func (c *Camera2D) SetContentScale(sx float32, sy float32) {
+	c.contentScale = mgl32.Vec2{sx, sy}
+}
 
-	x := math.Min(float64(x1), float64(x2))
-	y := math.Min(float64(y1), float64(y2))
+// Viewport returns the origin and the size of the screen area the camera renders into, in framebuffer pixels
+func (c *Camera2D) Viewport() (origin mgl32.Vec2, size mgl32.Vec2) {
+	return c.viewportOrigin, c.viewportSize
+}
+
+// SetViewport sets the screen area the camera renders into, in framebuffer pixels with the origin at the top-left
+// corner of the window. ScreenToWorld and WorldToScreen use it to convert from and to window coordinates. By default
+// the viewport starts at the top-left corner and has the size of the camera. Note that it doesn't call gl.Viewport
+func (c *Camera2D) SetViewport(x, y, width, height float32) error {
+	if width <= 0 || height <= 0 {
+		return errors.New("the viewport size must be positive")
+	}
+	c.viewportOrigin = mgl32.Vec2{x, y}
+	c.viewportSize = mgl32.Vec2{width, height}
+	return nil
+}
+
+// SetPixelSnap rounds the camera position to whole screen pixels, which avoids the shimmering of pixel art while
+// scrolling. The snapping is applied only when the zoom is an integer, and it makes sense only with textures using
+// nearest filtering. Primitives placed at fractional positions are still rendered between pixels
+func (c *Camera2D) SetPixelSnap(snap bool) {
+	c.pixelSnap = snap
+	c.matrixDirty = true
+}
+
+// SetVisibleArea configures the camera to make the specified area completely visible and centered on the screen,
+// position and zoom are changed accordingly
+func (c *Camera2D) SetVisibleArea(x1 float32, y1 float32, x2 float32, y2 float32) error {
+	return c.SetVisibleAreaWithPadding(x1, y1, x2, y2, 0)
+}
+
+// SetVisibleAreaWithPadding is like SetVisibleArea, leaving at least paddingPixels screen pixels between the area and
+// the edges of the screen. The corners can be passed in any order (see FrameBounds)
+func (c *Camera2D) SetVisibleAreaWithPadding(x1 float32, y1 float32, x2 float32, y2 float32, paddingPixels float32) error {
+	min, max := GetBoundingBox([]mgl32.Vec2{{x1, y1}, {x2, y2}})
+	return c.FrameBounds(min, max, paddingPixels)
+}
+
+// FrameBounds changes position and zoom to make the area between min and max visible and centered on the screen,
+// whether the camera is centered or not, leaving at least paddingPixels screen pixels between the area and the edges
+// of the screen. An area with zero width (or height) is fitted on its height (or width) only, an area with no extent
+// at all is an error
+func (c *Camera2D) FrameBounds(min, max mgl32.Vec2, paddingPixels float32) error {
+	availableWidth := float64(c.width - paddingPixels*2)
+	availableHeight := float64(c.height - paddingPixels*2)
+	if availableWidth <= 0 || availableHeight <= 0 {
+		return errors.New("the padding leaves no room on the screen")
+	}
+	width := math.Abs(float64(max.X() - min.X()))
+	height := math.Abs(float64(max.Y() - min.Y()))
+	var zoom float64
+	switch {
+	case width == 0 && height == 0:
+		return errors.New("the bounds must have a width or a height")
+	case width == 0:
+		zoom = availableHeight / height
+	case height == 0:
+		zoom = availableWidth / width
+	default:
+		zoom = math.Min(availableWidth/width, availableHeight/height)
+	}
+	c.SetZoom(float32(zoom))
+
+	center := min.Add(max).Mul(0.5)
 	if c.centered {
-		c.SetPosition(float32(x+width/2), float32(y+height/2))
+		c.SetPosition(center.X(), center.Y())
 	} else {
-		c.SetPosition(float32(x), float32(y))
+		// The zoom can be clamped, use the one actually set
+		c.SetPosition(center.X()-c.width/c.zoom/2, center.Y()-c.height/c.zoom/2)
 	}
+	return nil
+}
+
+// FramePrimitives changes position and zoom to make all the primitives visible (see FrameBounds)
+func (c *Camera2D) FramePrimitives(primitives []*Primitive2D, paddingPixels float32) error {
+	if len(primitives) == 0 {
+		return errors.New("no primitives to frame")
+	}
+	corners := make([]mgl32.Vec2, 0, len(primitives)*2)
+	for _, p := range primitives {
+		min, max := p.Bounds()
+		corners = append(corners, min, max)
+	}
+	min, max := GetBoundingBox(corners)
+	return c.FrameBounds(min, max, paddingPixels)
+}
+
+// StartPan starts dragging the camera from the screen position passed
+func (c *Camera2D) StartPan(screen mgl32.Vec2) {
+	c.panning = true
+	c.panLast = screen
+	c.panDelta = mgl32.Vec2{}
+	c.panVelocity = mgl32.Vec2{}
+}
+
+// UpdatePan moves the camera so that the world point under the pointer follows it
+func (c *Camera2D) UpdatePan(screen mgl32.Vec2) {
+	if !c.panning {
+		return
+	}
+	delta := screen.Sub(c.panLast)
+	delta = mgl32.Vec2{delta[0] * c.contentScale[0], delta[1] * c.contentScale[1]}
+	c.panLast = screen
+	worldDelta := mgl32.Vec2{-delta.X() / c.zoom, -delta.Y() / c.zoom}
+	c.Translate(worldDelta.X(), worldDelta.Y())
+	c.panDelta = c.panDelta.Add(worldDelta)
+}
+
+// EndPan stops dragging the camera, which keeps gliding with the speed of the drag
+func (c *Camera2D) EndPan() {
+	c.panning = false
+}
+
+// SetPanFriction sets how fast the camera glide slows down after EndPan. Higher values stop the camera sooner
+func (c *Camera2D) SetPanFriction(friction float32) {
+	c.panFriction = friction
+}
+
+// Update advances the camera movements by dt seconds
+func (c *Camera2D) Update(dt float32) {
+	if dt <= 0 {
+		return
+	}
+	c.updateZoom(dt)
+	if c.panning {
+		// Track the speed of the drag, it's used for the glide after EndPan
+		c.panVelocity = c.panDelta.Mul(1 / dt)
+		c.panDelta = mgl32.Vec2{}
+		return
+	}
+	// Stop when the glide is slower than one pixel per second
+	if c.panVelocity.Len()*c.zoom < 1 {
+		c.panVelocity = mgl32.Vec2{}
+		return
+	}
+	c.Translate(c.panVelocity.X()*dt, c.panVelocity.Y()*dt)
+	c.panVelocity = c.panVelocity.Mul(float32(math.Exp(float64(-c.panFriction * dt))))
+}
+
+// updateZoom advances the transition started by ZoomTo
+func (c *Camera2D) updateZoom(dt float32) {
+	if !c.zoomAnimating {
+		return
+	}
+	zoom := c.zoomTarget
+	remaining := c.zoomTarget - c.zoom
+	// Snap to the target when the difference isn't visible anymore
+	if float32(math.Abs(float64(remaining))) > c.zoomTarget*1e-4 {
+		zoom = c.zoom + remaining*float32(1-math.Exp(float64(-c.zoomSmoothing*dt)))
+	} else {
+		c.zoomAnimating = false
+	}
+	if c.zoomAnchored {
+		c.ZoomAt(c.zoomAnchor, zoom)
+	} else {
+		c.SetZoom(zoom)
+	}
+}
+
+// visibleArea returns the min and max corners of the world area visible through the camera
+func (c *Camera2D) visibleArea() (mgl32.Vec2, mgl32.Vec2) {
+	width := c.width / c.zoom
+	height := c.height / c.zoom
+	min := mgl32.Vec2{c.x, c.y}
+	if c.centered {
+		min = min.Sub(mgl32.Vec2{width / 2, height / 2})
+	}
+	return min, min.Add(mgl32.Vec2{width, height})
 }
 
 func (c *Camera2D) rebuildMatrix() {
@@ -141,10 +410,17 @@ func (c *Camera2D) rebuildMatrix() {
 		top = c.height / c.zoom
 	}
 
-	left += c.x
-	right += c.x
-	top += c.y
-	bottom += c.y
+	x, y := c.x, c.y
+	if c.pixelSnap && c.zoom == float32(math.Floor(float64(c.zoom))) {
+		// Move the camera only by whole screen pixels
+		x = float32(math.Round(float64(x*c.zoom))) / c.zoom
+		y = float32(math.Round(float64(y*c.zoom))) / c.zoom
+	}
+
+	left += x
+	right += x
+	top += y
+	bottom += y
 
 	if c.flipVertical {
 		bottom, top = top, bottom
@@ -155,21 +431,77 @@ func (c *Camera2D) rebuildMatrix() {
 	c.matrixDirty = false
 }
 
+// ScreenToWorld converts a point from screen coordinates (pixels) to world coordinates
 func (c *Camera2D) ScreenToWorld(vec mgl32.Vec2) mgl32.Vec3 {
-	if c.flipVertical {
-		vec[1] = c.height - vec[1]
+	c.rebuildMatrix()
+	return c.screenToWorld(vec)
+}
+
+// ScreenToWorldBatch converts many points from screen to world coordinates, it's faster than calling ScreenToWorld
+// for each point
+func (c *Camera2D) ScreenToWorldBatch(points []mgl32.Vec2) []mgl32.Vec3 {
+	c.rebuildMatrix()
+	result := make([]mgl32.Vec3, len(points))
+	for i, p := range points {
+		result[i] = c.screenToWorld(p)
 	}
-	x := (vec[0] - c.halfWidth) / c.halfWidth
-	y := (vec[1] - c.halfHeight) / c.halfHeight
+	return result
+}
+
+func (c *Camera2D) screenToWorld(vec mgl32.Vec2) mgl32.Vec3 {
+	vec = mgl32.Vec2{vec[0] * c.contentScale[0], vec[1] * c.contentScale[1]}
+	halfViewport := c.viewportSize.Mul(0.5)
+	// Screen Y goes down while normalized device Y goes up, the projection matrix takes care of flipVertical
+	x := (vec[0] - c.viewportOrigin[0] - halfViewport[0]) / halfViewport[0]
+	y := (halfViewport[1] - (vec[1] - c.viewportOrigin[1])) / halfViewport[1]
 	return mgl32.TransformCoordinate(mgl32.Vec3{x, y, 0}, c.inverseMatrix)
 }
 
+// WorldToScreen converts a point from world coordinates to screen coordinates (pixels)
 func (c *Camera2D) WorldToScreen(vec mgl32.Vec3) mgl32.Vec2 {
+	c.rebuildMatrix()
+	return c.worldToScreen(vec)
+}
+
+// WorldToScreenBatch converts many points from world to screen coordinates, it's faster than calling WorldToScreen
+// for each point
+func (c *Camera2D) WorldToScreenBatch(points []mgl32.Vec3) []mgl32.Vec2 {
+	c.rebuildMatrix()
+	result := make([]mgl32.Vec2, len(points))
+	for i, p := range points {
+		result[i] = c.worldToScreen(p)
+	}
+	return result
+}
+
+func (c *Camera2D) worldToScreen(vec mgl32.Vec3) mgl32.Vec2 {
 	ret := mgl32.TransformCoordinate(vec, c.projectionMatrix)
-	ret[0] = ret[0]*c.halfWidth + c.halfWidth
-	ret[1] = ret[1]*c.halfHeight + c.halfHeight
-	if c.flipVertical {
-		ret[1] = c.height - ret[1]
+	halfViewport := c.viewportSize.Mul(0.5)
+	ret[0] = c.viewportOrigin[0] + ret[0]*halfViewport[0] + halfViewport[0]
+	ret[1] = c.viewportOrigin[1] + halfViewport[1] - ret[1]*halfViewport[1]
+	return mgl32.Vec2{ret[0] / c.contentScale[0], ret[1] / c.contentScale[1]}
+}
+
+// WorldRectToScreen converts a rectangle from world to screen coordinates. The result is the screen box enclosing
+// all the four corners of the rectangle
+func (c *Camera2D) WorldRectToScreen(min, max mgl32.Vec2) (screenMin, screenMax mgl32.Vec2) {
+	corners := []mgl32.Vec2{
+		c.WorldToScreen(mgl32.Vec3{min.X(), min.Y(), 0}),
+		c.WorldToScreen(mgl32.Vec3{max.X(), min.Y(), 0}),
+		c.WorldToScreen(mgl32.Vec3{max.X(), max.Y(), 0}),
+		c.WorldToScreen(mgl32.Vec3{min.X(), max.Y(), 0}),
+	}
+	return GetBoundingBox(corners)
+}
+
+// ScreenRectToWorld converts a rectangle from screen to world coordinates. The result is the world box enclosing
+// all the four corners of the rectangle
+func (c *Camera2D) ScreenRectToWorld(min, max mgl32.Vec2) (worldMin, worldMax mgl32.Vec2) {
+	corners := []mgl32.Vec2{
+		c.ScreenToWorld(mgl32.Vec2{min.X(), min.Y()}).Vec2(),
+		c.ScreenToWorld(mgl32.Vec2{max.X(), min.Y()}).Vec2(),
+		c.ScreenToWorld(mgl32.Vec2{max.X(), max.Y()}).Vec2(),
+		c.ScreenToWorld(mgl32.Vec2{min.X(), max.Y()}).Vec2(),
 	}
-	return mgl32.Vec2{ret[0], ret[1]}
+	return GetBoundingBox(corners)
 }
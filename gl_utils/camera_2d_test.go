@@ -0,0 +1,243 @@
+package gl_utils
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+var invalidZooms = []float32{
+	0,
+	-1,
+	float32(math.NaN()),
+	float32(math.Inf(1)),
+	float32(math.Inf(-1)),
+}
+
+// finiteMatrix returns whether all the elements of the matrix are finite numbers
+func finiteMatrix(m *mgl32.Mat4) bool {
+	for _, value := range m {
+		if math.IsNaN(float64(value)) || math.IsInf(float64(value), 0) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestNewCamera2DInvalidZoom(t *testing.T) {
+	for _, zoom := range invalidZooms {
+		c := NewCamera2D(800, 600, zoom)
+		if c.Zoom() != 1 {
+			t.Errorf("NewCamera2D with zoom %v: Zoom() = %v, expected 1", zoom, c.Zoom())
+		}
+		if !finiteMatrix(c.ProjectionMatrix()) {
+			t.Errorf("NewCamera2D with zoom %v: degenerate projection %v", zoom, *c.ProjectionMatrix())
+		}
+	}
+}
+
+func TestNewCamera2DClampsZoom(t *testing.T) {
+	c := NewCamera2D(800, 600, 1000)
+	if c.Zoom() != c.MaxZoom() {
+		t.Errorf("Zoom() = %v, expected the maximum zoom %v", c.Zoom(), c.MaxZoom())
+	}
+}
+
+func TestSetZoomIgnoresInvalidValues(t *testing.T) {
+	c := NewCamera2D(800, 600, 2)
+	for _, zoom := range invalidZooms {
+		c.SetZoom(zoom)
+		if c.Zoom() != 2 {
+			t.Errorf("SetZoom(%v) changed the zoom to %v", zoom, c.Zoom())
+		}
+		if !finiteMatrix(c.ProjectionMatrix()) {
+			t.Errorf("SetZoom(%v): degenerate projection %v", zoom, *c.ProjectionMatrix())
+		}
+	}
+}
+
+func TestSetZoomRange(t *testing.T) {
+	c := NewCamera2D(800, 600, 2)
+	tests := []struct {
+		name     string
+		min, max float32
+		valid    bool
+	}{
+		{"inverted", 4, 1, false},
+		{"zero minimum", 0, 1, false},
+		{"NaN minimum", float32(math.NaN()), 1, false},
+		{"infinite maximum", 1, float32(math.Inf(1)), false},
+		{"negative", -2, -1, false},
+		{"single value", 3, 3, true},
+	}
+	for _, test := range tests {
+		minZoom, maxZoom := c.MinZoom(), c.MaxZoom()
+		err := c.SetZoomRange(test.min, test.max)
+		if test.valid {
+			if err != nil {
+				t.Errorf("%s: unexpected error %v", test.name, err)
+			}
+			continue
+		}
+		if err == nil {
+			t.Errorf("%s: SetZoomRange(%v, %v) accepted", test.name, test.min, test.max)
+		}
+		if c.MinZoom() != minZoom || c.MaxZoom() != maxZoom {
+			t.Errorf("%s: the range changed to %v-%v", test.name, c.MinZoom(), c.MaxZoom())
+		}
+	}
+	// The current zoom is clamped to the new range
+	if c.Zoom() != 3 {
+		t.Errorf("Zoom() = %v, expected 3", c.Zoom())
+	}
+}
+
+func TestSetVisibleAreaDegenerate(t *testing.T) {
+	c := NewCamera2D(800, 600, 1)
+	if err := c.SetVisibleArea(10, 10, 10, 10); err == nil {
+		t.Error("an area with no extent has been accepted")
+	}
+	if c.Zoom() != 1 {
+		t.Errorf("a rejected area changed the zoom to %v", c.Zoom())
+	}
+
+	// Zero width, fitted on the height
+	if err := c.SetVisibleArea(10, 0, 10, 300); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if c.Zoom() != 2 {
+		t.Errorf("Zoom() = %v, expected 2", c.Zoom())
+	}
+	if !finiteMatrix(c.ProjectionMatrix()) {
+		t.Errorf("degenerate projection %v", *c.ProjectionMatrix())
+	}
+
+	if err := c.SetVisibleAreaWithPadding(0, 0, 100, 100, 400); err == nil {
+		t.Error("a padding larger than the screen has been accepted")
+	}
+}
+
+func TestFrameBoundsDegenerate(t *testing.T) {
+	c := NewCamera2D(800, 600, 1)
+	point := mgl32.Vec2{5, 5}
+	if err := c.FrameBounds(point, point, 0); err == nil {
+		t.Error("bounds with no extent have been accepted")
+	}
+	if c.Zoom() != 1 {
+		t.Errorf("rejected bounds changed the zoom to %v", c.Zoom())
+	}
+
+	// Zero height, fitted on the width
+	if err := c.FrameBounds(mgl32.Vec2{0, 5}, mgl32.Vec2{200, 5}, 0); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if c.Zoom() != 4 {
+		t.Errorf("Zoom() = %v, expected 4", c.Zoom())
+	}
+	if !finiteMatrix(c.ProjectionMatrix()) {
+		t.Errorf("degenerate projection %v", *c.ProjectionMatrix())
+	}
+}
+
+// closeVec2 returns whether the two points are closer than a thousandth of a unit on both axes
+func closeVec2(a, b mgl32.Vec2) bool {
+	return math.Abs(float64(a.X()-b.X())) < 1e-3 && math.Abs(float64(a.Y()-b.Y())) < 1e-3
+}
+
+func TestScreenWorldRoundTripWithViewport(t *testing.T) {
+	screenPoints := []mgl32.Vec2{{100, 50}, {500, 350}, {300, 200}, {123.5, 321.25}, {0, 0}}
+	for _, flip := range []bool{false, true} {
+		for _, centered := range []bool{false, true} {
+			c := NewCamera2D(800, 600, 2)
+			c.SetFlipVertical(flip)
+			c.SetCentered(centered)
+			c.SetPosition(30, -40)
+			if err := c.SetViewport(100, 50, 400, 300); err != nil {
+				t.Fatal(err)
+			}
+			for _, screen := range screenPoints {
+				world := c.ScreenToWorld(screen)
+				back := c.WorldToScreen(world)
+				if !closeVec2(back, screen) {
+					t.Errorf("flip %v, centered %v: %v -> %v -> %v", flip, centered, screen, world, back)
+				}
+			}
+		}
+	}
+}
+
+func TestViewportCornersMapToVisibleArea(t *testing.T) {
+	for _, flip := range []bool{false, true} {
+		c := NewCamera2D(800, 600, 2)
+		c.SetFlipVertical(flip)
+		c.SetPosition(30, -40)
+		if err := c.SetViewport(100, 50, 400, 300); err != nil {
+			t.Fatal(err)
+		}
+		// The camera shows 400x300 world units, starting from its position
+		topLeft, bottomRight := mgl32.Vec2{30, -40}, mgl32.Vec2{430, 260}
+		if flip {
+			topLeft, bottomRight = mgl32.Vec2{30, 260}, mgl32.Vec2{430, -40}
+		}
+		if world := c.ScreenToWorld(mgl32.Vec2{100, 50}).Vec2(); !closeVec2(world, topLeft) {
+			t.Errorf("flip %v: the top-left corner of the viewport is %v, expected %v", flip, world, topLeft)
+		}
+		if world := c.ScreenToWorld(mgl32.Vec2{500, 350}).Vec2(); !closeVec2(world, bottomRight) {
+			t.Errorf("flip %v: the bottom-right corner of the viewport is %v, expected %v", flip, world, bottomRight)
+		}
+	}
+}
+
+func TestScreenToWorldVerticalAxis(t *testing.T) {
+	// Without flipVertical the world Y grows downwards like the screen Y
+	c := NewCamera2D(800, 600, 1)
+	if world := c.ScreenToWorld(mgl32.Vec2{10, 20}).Vec2(); !closeVec2(world, mgl32.Vec2{10, 20}) {
+		t.Errorf("ScreenToWorld(10, 20) = %v, expected (10, 20)", world)
+	}
+	if screen := c.WorldToScreen(mgl32.Vec3{10, 20, 0}); !closeVec2(screen, mgl32.Vec2{10, 20}) {
+		t.Errorf("WorldToScreen(10, 20) = %v, expected (10, 20)", screen)
+	}
+
+	// With flipVertical the world Y grows upwards from the bottom of the screen
+	c.SetFlipVertical(true)
+	if world := c.ScreenToWorld(mgl32.Vec2{10, 20}).Vec2(); !closeVec2(world, mgl32.Vec2{10, 580}) {
+		t.Errorf("flipped ScreenToWorld(10, 20) = %v, expected (10, 580)", world)
+	}
+	if screen := c.WorldToScreen(mgl32.Vec3{10, 580, 0}); !closeVec2(screen, mgl32.Vec2{10, 20}) {
+		t.Errorf("flipped WorldToScreen(10, 580) = %v, expected (10, 20)", screen)
+	}
+}
+
+func TestFrameAreaPlacement(t *testing.T) {
+	frames := []struct {
+		name  string
+		frame func(c *Camera2D) error
+	}{
+		{"SetVisibleAreaWithPadding", func(c *Camera2D) error {
+			return c.SetVisibleAreaWithPadding(300, 150, 100, 50, 100)
+		}},
+		{"FrameBounds", func(c *Camera2D) error {
+			return c.FrameBounds(mgl32.Vec2{100, 50}, mgl32.Vec2{300, 150}, 100)
+		}},
+	}
+	for _, frame := range frames {
+		for _, centered := range []bool{false, true} {
+			c := NewCamera2D(800, 600, 1)
+			c.SetCentered(centered)
+			if err := frame.frame(c); err != nil {
+				t.Fatalf("%s, centered %v: %v", frame.name, centered, err)
+			}
+			if c.Zoom() != 3 {
+				t.Errorf("%s, centered %v: Zoom() = %v, expected 3", frame.name, centered, c.Zoom())
+			}
+			// The area is centered on the screen, with the padding on the sides where it fits tightly
+			if screen := c.WorldToScreen(mgl32.Vec3{200, 100, 0}); !closeVec2(screen, mgl32.Vec2{400, 300}) {
+				t.Errorf("%s, centered %v: the center of the area is at %v on the screen", frame.name, centered, screen)
+			}
+			if screen := c.WorldToScreen(mgl32.Vec3{100, 50, 0}); !closeVec2(screen, mgl32.Vec2{100, 150}) {
+				t.Errorf("%s, centered %v: the top-left corner of the area is at %v on the screen", frame.name, centered, screen)
+			}
+		}
+	}
+}
@@ -0,0 +1,23 @@
+package gl_utils
+
+import (
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// Clear fills the color buffer of the current framebuffer with a color
+func Clear(color Color) {
+	glc.ClearColor(color[0], color[1], color[2], color[3])
+	glc.Clear(gl.COLOR_BUFFER_BIT)
+}
+
+// ClearDepth resets the depth buffer of the current framebuffer
+func ClearDepth() {
+	glc.Clear(gl.DEPTH_BUFFER_BIT)
+}
+
+// ClearAll fills the color buffer with a color and resets the depth buffer, use it at the beginning of every frame
+// when depth testing is enabled
+func ClearAll(color Color) {
+	glc.ClearColor(color[0], color[1], color[2], color[3])
+	glc.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
+}
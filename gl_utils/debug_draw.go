@@ -0,0 +1,109 @@
+package gl_utils
+
+import (
+	"math"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+const (
+	// Number of floats per vertex in the debug draw buffer: x, y, r, g, b, a
+	debugDrawVertexSize = 6
+	// Number of segments used to draw a circle
+	debugDrawCircleSegments = 32
+)
+
+// DebugDraw accumulates lines and simple shapes during a frame and draws all of them at once with Flush. Each shape
+// has its own color
+type DebugDraw struct {
+	vertices      []float32
+	shaderProgram *ShaderProgram
+	vaoId         uint32
+	vbo           uint32
+	identity      mgl32.Mat4
+}
+
+// NewDebugDraw creates an empty debug drawer
+func NewDebugDraw() *DebugDraw {
+	d := &DebugDraw{
+		shaderProgram: SharedShaderProgram(VertexShaderVertexColor, "", FragmentShaderVertexColor),
+		identity:      mgl32.Ident4(),
+	}
+	glc.GenVertexArrays(1, &d.vaoId)
+	glc.BindVertexArray(d.vaoId)
+	glc.GenBuffers(1, &d.vbo)
+	glc.BindBuffer(gl.ARRAY_BUFFER, d.vbo)
+	stride := int32(debugDrawVertexSize * Float32Size)
+	glc.EnableVertexAttribArray(0)
+	glc.VertexAttribPointer(0, 2, gl.FLOAT, false, stride, gl.PtrOffset(0))
+	glc.EnableVertexAttribArray(2)
+	glc.VertexAttribPointer(2, 4, gl.FLOAT, false, stride, gl.PtrOffset(2*Float32Size))
+	glc.BindVertexArray(0)
+	return d
+}
+
+// Line adds a segment from a to b
+func (d *DebugDraw) Line(a, b mgl32.Vec2, color Color) {
+	d.vertices = append(d.vertices,
+		a.X(), a.Y(), color[0], color[1], color[2], color[3],
+		b.X(), b.Y(), color[0], color[1], color[2], color[3],
+	)
+}
+
+// Rect adds the outline of the rectangle having min and max as opposite corners
+func (d *DebugDraw) Rect(min, max mgl32.Vec2, color Color) {
+	topRight := mgl32.Vec2{max.X(), min.Y()}
+	bottomLeft := mgl32.Vec2{min.X(), max.Y()}
+	d.Line(min, topRight, color)
+	d.Line(topRight, max, color)
+	d.Line(max, bottomLeft, color)
+	d.Line(bottomLeft, min, color)
+}
+
+// Circle adds the outline of a circle
+func (d *DebugDraw) Circle(center mgl32.Vec2, radius float32, color Color) {
+	step := 2 * math.Pi / debugDrawCircleSegments
+	previous := center.Add(mgl32.Vec2{radius, 0})
+	for i := 1; i <= debugDrawCircleSegments; i++ {
+		angle := step * float64(i)
+		next := center.Add(mgl32.Vec2{radius * float32(math.Cos(angle)), radius * float32(math.Sin(angle))})
+		d.Line(previous, next, color)
+		previous = next
+	}
+}
+
+// Point adds a small cross centered on the point, size is the length of its arms
+func (d *DebugDraw) Point(p mgl32.Vec2, size float32, color Color) {
+	half := size / 2
+	d.Line(p.Sub(mgl32.Vec2{half, 0}), p.Add(mgl32.Vec2{half, 0}), color)
+	d.Line(p.Sub(mgl32.Vec2{0, half}), p.Add(mgl32.Vec2{0, half}), color)
+}
+
+// Flush draws all the shapes added since the previous flush with a single draw call, then forgets them
+func (d *DebugDraw) Flush(projectionMatrix *mgl32.Mat4) {
+	if len(d.vertices) == 0 {
+		return
+	}
+	numVertices := int32(len(d.vertices) / debugDrawVertexSize)
+
+	glc.BindBuffer(gl.ARRAY_BUFFER, d.vbo)
+	glc.BufferData(gl.ARRAY_BUFFER, len(d.vertices)*Float32Size, gl.Ptr(d.vertices), gl.STREAM_DRAW)
+	countBufferUpload()
+
+	glc.UseProgram(d.shaderProgram.ID())
+	d.shaderProgram.SetUniform("projection", projectionMatrix)
+	d.shaderProgram.SetUniform("model", &d.identity)
+	glc.BindVertexArray(d.vaoId)
+	glc.DrawArrays(gl.LINES, 0, numVertices)
+	glc.BindVertexArray(0)
+	countDrawCall(int(numVertices))
+
+	d.vertices = d.vertices[:0]
+}
+
+// Release deletes the vertex array and the buffer of the debug drawer
+func (d *DebugDraw) Release() {
+	glc.DeleteBuffers(1, &d.vbo)
+	glc.DeleteVertexArrays(1, &d.vaoId)
+}
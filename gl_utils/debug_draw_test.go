@@ -0,0 +1,33 @@
+package gl_utils
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestDebugDrawFlush(t *testing.T) {
+	r := useRecordingGL(t)
+	d := NewDebugDraw()
+	d.Line(mgl32.Vec2{0, 0}, mgl32.Vec2{1, 1}, Color{1, 0, 0, 1})
+	d.Rect(mgl32.Vec2{0, 0}, mgl32.Vec2{1, 1}, Color{0, 1, 0, 1})
+	projection := mgl32.Ident4()
+	r.reset()
+
+	d.Flush(&projection)
+
+	// A line and the 4 sides of the rectangle, with a single draw call
+	expected := []string{fmt.Sprintf("DrawArrays(%d, 0, 10)", gl.LINES)}
+	if calls := drawCalls(r); fmt.Sprint(calls) != fmt.Sprint(expected) {
+		t.Errorf("draw calls %v, expected %v", calls, expected)
+	}
+
+	// The shapes are forgotten after the flush
+	r.reset()
+	d.Flush(&projection)
+	if calls := drawCalls(r); len(calls) != 0 {
+		t.Errorf("draw calls %v after a second flush", calls)
+	}
+}
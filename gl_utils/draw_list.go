@@ -0,0 +1,84 @@
+package gl_utils
+
+import (
+	"sort"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// DrawList collects primitives and draws them in the order needed to blend the transparent ones correctly: the
+// opaque primitives first, front to back, then the transparent ones, back to front, without writing the depth
+type DrawList struct {
+	opaque      []drawListItem
+	transparent []drawListItem
+}
+
+type drawListItem struct {
+	primitive *Primitive2D
+	depth     float32
+}
+
+// NewDrawList creates an empty draw list
+func NewDrawList() *DrawList {
+	return &DrawList{}
+}
+
+// Add adds a primitive to the list. The primitive is considered transparent if it has been flagged with
+// SetTransparent or its color isn't fully opaque
+func (l *DrawList) Add(primitive *Primitive2D) {
+	item := drawListItem{primitive: primitive}
+	if primitive.transparent || primitive.drawColor().A() < 1 {
+		l.transparent = append(l.transparent, item)
+	} else {
+		l.opaque = append(l.opaque, item)
+	}
+}
+
+// Len returns the number of primitives in the list
+func (l *DrawList) Len() int {
+	return len(l.opaque) + len(l.transparent)
+}
+
+// Clear removes all the primitives from the list
+func (l *DrawList) Clear() {
+	l.opaque = l.opaque[:0]
+	l.transparent = l.transparent[:0]
+}
+
+// Draw sorts the primitives by their depth as seen through the projection and draws them. The depth test is enabled
+// while drawing and the depth mask is left enabled
+func (l *DrawList) Draw(projectionMatrix *mgl32.Mat4) {
+	depthTestEnabled := glc.IsEnabled(gl.DEPTH_TEST)
+	glc.Enable(gl.DEPTH_TEST)
+
+	computeDrawListDepths(l.opaque, projectionMatrix)
+	sort.SliceStable(l.opaque, func(i, j int) bool {
+		return l.opaque[i].depth < l.opaque[j].depth
+	})
+	glc.DepthMask(true)
+	for _, item := range l.opaque {
+		item.primitive.Draw(projectionMatrix)
+	}
+
+	computeDrawListDepths(l.transparent, projectionMatrix)
+	sort.SliceStable(l.transparent, func(i, j int) bool {
+		return l.transparent[i].depth > l.transparent[j].depth
+	})
+	glc.DepthMask(false)
+	for _, item := range l.transparent {
+		item.primitive.Draw(projectionMatrix)
+	}
+	glc.DepthMask(true)
+
+	if !depthTestEnabled {
+		glc.Disable(gl.DEPTH_TEST)
+	}
+}
+
+// computeDrawListDepths sets the depth of each item to the normalized depth of its primitive position
+func computeDrawListDepths(items []drawListItem, projectionMatrix *mgl32.Mat4) {
+	for i := range items {
+		items[i].depth = mgl32.TransformCoordinate(items[i].primitive.position, *projectionMatrix).Z()
+	}
+}
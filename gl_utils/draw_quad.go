@@ -0,0 +1,51 @@
+package gl_utils
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Quad shared by all the DrawQuad calls and its shaders, created on first use
+var (
+	immediateQuad          *Primitive2D
+	immediateSolidShader   *ShaderProgram
+	immediateTextureShader *ShaderProgram
+)
+
+// DrawQuad draws a rectangle with its top-left corner at pos, filled with the texture tinted by color, or with the
+// plain color if texture is nil. It's meant for prototyping: nothing has to be created or released, but every call
+// is a separate draw call changing shader and uniforms. To draw many quads create the primitives once and draw them
+// with a RenderQueue
+func DrawQuad(projectionMatrix *mgl32.Mat4, pos mgl32.Vec3, size mgl32.Vec2, texture *Texture, color Color) {
+	if immediateQuad == nil {
+		immediateQuad = NewQuadPrimitiveExt(mgl32.Vec3{}, mgl32.Vec2{1, 1}, nil, nil, nil)
+		immediateSolidShader = SharedShaderProgram(VertexShaderBase, "", FragmentShaderSolidColor)
+		immediateTextureShader = SharedShaderProgram(VertexShaderBase, "", FragmentShaderTintedTexture)
+	}
+	if texture != nil {
+		immediateQuad.SetShader(immediateTextureShader)
+	} else {
+		immediateQuad.SetShader(immediateSolidShader)
+	}
+	immediateQuad.SetTexture(texture)
+	immediateQuad.SetPosition(pos)
+	immediateQuad.SetSize(size)
+	immediateQuad.SetColor(color)
+	immediateQuad.Draw(projectionMatrix)
+}
+
+const (
+	// FragmentShaderTintedTexture multiplies the texture by the 'color' uniform
+	FragmentShaderTintedTexture = `
+        #version 410 core
+
+        in vec2 uv_out;
+        out vec4 out_color;
+        uniform vec4 color;
+
+        uniform sampler2D tex;
+
+        void main() {
+            out_color = color * texture(tex, uv_out);
+        }
+        ` + "\x00"
+)
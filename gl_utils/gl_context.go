@@ -0,0 +1,441 @@
+package gl_utils
+
+import (
+	"unsafe"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// glContext the subset of the OpenGL API used by the package. Every GL call of the package goes through glc, which
+// can be replaced (e.g. by a recorder in tests) to run without a GL context
+type glContext interface {
+	// Vertex arrays and buffers
+	GenVertexArrays(n int32, arrays *uint32)
+	BindVertexArray(array uint32)
+	DeleteVertexArrays(n int32, arrays *uint32)
+	GenBuffers(n int32, buffers *uint32)
+	DeleteBuffers(n int32, buffers *uint32)
+	BindBuffer(target uint32, buffer uint32)
+	BufferData(target uint32, size int, data unsafe.Pointer, usage uint32)
+	BufferSubData(target uint32, offset int, size int, data unsafe.Pointer)
+	EnableVertexAttribArray(index uint32)
+	VertexAttribPointer(index uint32, size int32, xtype uint32, normalized bool, stride int32, pointer unsafe.Pointer)
+	DrawArrays(mode uint32, first int32, count int32)
+	VertexAttribDivisor(index uint32, divisor uint32)
+	DrawArraysInstanced(mode uint32, first int32, count int32, instancecount int32)
+	MapBufferRange(target uint32, offset int, length int, access uint32) unsafe.Pointer
+	UnmapBuffer(target uint32) bool
+
+	// Shader programs
+	CreateProgram() uint32
+	DeleteProgram(program uint32)
+	CreateShader(xtype uint32) uint32
+	ShaderSource(shader uint32, count int32, xstring **uint8, length *int32)
+	CompileShader(shader uint32)
+	GetShaderiv(shader uint32, pname uint32, params *int32)
+	GetShaderInfoLog(shader uint32, bufSize int32, length *int32, infoLog *uint8)
+	AttachShader(program uint32, shader uint32)
+	LinkProgram(program uint32)
+	ValidateProgram(program uint32)
+	GetProgramiv(program uint32, pname uint32, params *int32)
+	GetProgramInfoLog(program uint32, bufSize int32, length *int32, infoLog *uint8)
+	GetAttribLocation(program uint32, name *uint8) int32
+	GetActiveAttrib(program uint32, index uint32, bufSize int32, length *int32, size *int32, xtype *uint32, name *uint8)
+	GetActiveUniform(program uint32, index uint32, bufSize int32, length *int32, size *int32, xtype *uint32, name *uint8)
+	UseProgram(program uint32)
+
+	// Uniforms
+	GetUniformLocation(program uint32, name *uint8) int32
+	GetUniformfv(program uint32, location int32, params *float32)
+	GetUniformiv(program uint32, location int32, params *int32)
+	GetUniformuiv(program uint32, location int32, params *uint32)
+	GetUniformdv(program uint32, location int32, params *float64)
+	Uniform1f(location int32, v0 float32)
+	Uniform1iv(location int32, count int32, value *int32)
+	Uniform1fv(location int32, count int32, value *float32)
+	Uniform2fv(location int32, count int32, value *float32)
+	Uniform3fv(location int32, count int32, value *float32)
+	Uniform4fv(location int32, count int32, value *float32)
+	UniformMatrix2fv(location int32, count int32, transpose bool, value *float32)
+	UniformMatrix3fv(location int32, count int32, transpose bool, value *float32)
+	UniformMatrix4fv(location int32, count int32, transpose bool, value *float32)
+
+	// Render state
+	Enable(cap uint32)
+	Disable(cap uint32)
+	IsEnabled(cap uint32) bool
+	GetIntegerv(pname uint32, data *int32)
+	GetBooleanv(pname uint32, data *bool)
+	BlendFunc(sfactor uint32, dfactor uint32)
+	BlendFuncSeparate(sfactorRGB uint32, dfactorRGB uint32, sfactorAlpha uint32, dfactorAlpha uint32)
+	DepthMask(flag bool)
+	CullFace(mode uint32)
+	FrontFace(mode uint32)
+	Viewport(x int32, y int32, width int32, height int32)
+	ClearColor(red float32, green float32, blue float32, alpha float32)
+	Clear(mask uint32)
+
+	// Errors and debug output
+	GetError() uint32
+	GetStringi(name uint32, index uint32) *uint8
+	DebugMessageCallback(callback gl.DebugProc, userParam unsafe.Pointer)
+
+	// Framebuffers
+	GenFramebuffers(n int32, framebuffers *uint32)
+	DeleteFramebuffers(n int32, framebuffers *uint32)
+	BindFramebuffer(target uint32, framebuffer uint32)
+	FramebufferTexture2D(target uint32, attachment uint32, textarget uint32, texture uint32, level int32)
+	FramebufferRenderbuffer(target uint32, attachment uint32, renderbuffertarget uint32, renderbuffer uint32)
+	CheckFramebufferStatus(target uint32) uint32
+	GenRenderbuffers(n int32, renderbuffers *uint32)
+	DeleteRenderbuffers(n int32, renderbuffers *uint32)
+	BindRenderbuffer(target uint32, renderbuffer uint32)
+	RenderbufferStorage(target uint32, internalformat uint32, width int32, height int32)
+	ReadPixels(x int32, y int32, width int32, height int32, format uint32, xtype uint32, pixels unsafe.Pointer)
+
+	// Textures
+	GenTextures(n int32, textures *uint32)
+	DeleteTextures(n int32, textures *uint32)
+	ActiveTexture(texture uint32)
+	BindTexture(target uint32, texture uint32)
+	TexParameteri(target uint32, pname uint32, param int32)
+	PixelStorei(pname uint32, param int32)
+	TexImage2D(target uint32, level int32, internalformat int32, width int32, height int32, border int32, format uint32, xtype uint32, pixels unsafe.Pointer)
+	TexSubImage2D(target uint32, level int32, xoffset int32, yoffset int32, width int32, height int32, format uint32, xtype uint32, pixels unsafe.Pointer)
+	TexImage3D(target uint32, level int32, internalformat int32, width int32, height int32, depth int32, border int32, format uint32, xtype uint32, pixels unsafe.Pointer)
+	TexSubImage3D(target uint32, level int32, xoffset int32, yoffset int32, zoffset int32, width int32, height int32, depth int32, format uint32, xtype uint32, pixels unsafe.Pointer)
+}
+
+// glc the OpenGL implementation in use, go-gl by default
+var glc glContext = goGLContext{}
+
+// goGLContext forwards the calls to go-gl
+type goGLContext struct{}
+
+func (goGLContext) GenVertexArrays(n int32, arrays *uint32) {
+	gl.GenVertexArrays(n, arrays)
+}
+
+func (goGLContext) BindVertexArray(array uint32) {
+	gl.BindVertexArray(array)
+}
+
+func (goGLContext) DeleteVertexArrays(n int32, arrays *uint32) {
+	gl.DeleteVertexArrays(n, arrays)
+}
+
+func (goGLContext) GenBuffers(n int32, buffers *uint32) {
+	gl.GenBuffers(n, buffers)
+}
+
+func (goGLContext) DeleteBuffers(n int32, buffers *uint32) {
+	gl.DeleteBuffers(n, buffers)
+}
+
+func (goGLContext) BindBuffer(target uint32, buffer uint32) {
+	gl.BindBuffer(target, buffer)
+}
+
+func (goGLContext) BufferData(target uint32, size int, data unsafe.Pointer, usage uint32) {
+	gl.BufferData(target, size, data, usage)
+}
+
+func (goGLContext) BufferSubData(target uint32, offset int, size int, data unsafe.Pointer) {
+	gl.BufferSubData(target, offset, size, data)
+}
+
+func (goGLContext) EnableVertexAttribArray(index uint32) {
+	gl.EnableVertexAttribArray(index)
+}
+
+func (goGLContext) VertexAttribPointer(index uint32, size int32, xtype uint32, normalized bool, stride int32, pointer unsafe.Pointer) {
+	gl.VertexAttribPointer(index, size, xtype, normalized, stride, pointer)
+}
+
+func (goGLContext) DrawArrays(mode uint32, first int32, count int32) {
+	gl.DrawArrays(mode, first, count)
+}
+
+func (goGLContext) VertexAttribDivisor(index uint32, divisor uint32) {
+	gl.VertexAttribDivisor(index, divisor)
+}
+
+func (goGLContext) DrawArraysInstanced(mode uint32, first int32, count int32, instancecount int32) {
+	gl.DrawArraysInstanced(mode, first, count, instancecount)
+}
+
+func (goGLContext) MapBufferRange(target uint32, offset int, length int, access uint32) unsafe.Pointer {
+	return gl.MapBufferRange(target, offset, length, access)
+}
+
+func (goGLContext) UnmapBuffer(target uint32) bool {
+	return gl.UnmapBuffer(target)
+}
+
+func (goGLContext) CreateProgram() uint32 {
+	return gl.CreateProgram()
+}
+
+func (goGLContext) DeleteProgram(program uint32) {
+	gl.DeleteProgram(program)
+}
+
+func (goGLContext) CreateShader(xtype uint32) uint32 {
+	return gl.CreateShader(xtype)
+}
+
+func (goGLContext) ShaderSource(shader uint32, count int32, xstring **uint8, length *int32) {
+	gl.ShaderSource(shader, count, xstring, length)
+}
+
+func (goGLContext) CompileShader(shader uint32) {
+	gl.CompileShader(shader)
+}
+
+func (goGLContext) GetShaderiv(shader uint32, pname uint32, params *int32) {
+	gl.GetShaderiv(shader, pname, params)
+}
+
+func (goGLContext) GetShaderInfoLog(shader uint32, bufSize int32, length *int32, infoLog *uint8) {
+	gl.GetShaderInfoLog(shader, bufSize, length, infoLog)
+}
+
+func (goGLContext) AttachShader(program uint32, shader uint32) {
+	gl.AttachShader(program, shader)
+}
+
+func (goGLContext) LinkProgram(program uint32) {
+	gl.LinkProgram(program)
+}
+
+func (goGLContext) ValidateProgram(program uint32) {
+	gl.ValidateProgram(program)
+}
+
+func (goGLContext) GetProgramiv(program uint32, pname uint32, params *int32) {
+	gl.GetProgramiv(program, pname, params)
+}
+
+func (goGLContext) GetProgramInfoLog(program uint32, bufSize int32, length *int32, infoLog *uint8) {
+	gl.GetProgramInfoLog(program, bufSize, length, infoLog)
+}
+
+func (goGLContext) GetAttribLocation(program uint32, name *uint8) int32 {
+	return gl.GetAttribLocation(program, name)
+}
+
+func (goGLContext) GetActiveAttrib(program uint32, index uint32, bufSize int32, length *int32, size *int32, xtype *uint32, name *uint8) {
+	gl.GetActiveAttrib(program, index, bufSize, length, size, xtype, name)
+}
+
+func (goGLContext) GetActiveUniform(program uint32, index uint32, bufSize int32, length *int32, size *int32, xtype *uint32, name *uint8) {
+	gl.GetActiveUniform(program, index, bufSize, length, size, xtype, name)
+}
+
+func (goGLContext) UseProgram(program uint32) {
+	gl.UseProgram(program)
+}
+
+func (goGLContext) GetUniformLocation(program uint32, name *uint8) int32 {
+	return gl.GetUniformLocation(program, name)
+}
+
+func (goGLContext) GetUniformfv(program uint32, location int32, params *float32) {
+	gl.GetUniformfv(program, location, params)
+}
+
+func (goGLContext) GetUniformiv(program uint32, location int32, params *int32) {
+	gl.GetUniformiv(program, location, params)
+}
+
+func (goGLContext) GetUniformuiv(program uint32, location int32, params *uint32) {
+	gl.GetUniformuiv(program, location, params)
+}
+
+func (goGLContext) GetUniformdv(program uint32, location int32, params *float64) {
+	gl.GetUniformdv(program, location, params)
+}
+
+func (goGLContext) Uniform1f(location int32, v0 float32) {
+	gl.Uniform1f(location, v0)
+}
+
+func (goGLContext) Uniform1iv(location int32, count int32, value *int32) {
+	gl.Uniform1iv(location, count, value)
+}
+
+func (goGLContext) Uniform1fv(location int32, count int32, value *float32) {
+	gl.Uniform1fv(location, count, value)
+}
+
+func (goGLContext) Uniform2fv(location int32, count int32, value *float32) {
+	gl.Uniform2fv(location, count, value)
+}
+
+func (goGLContext) Uniform3fv(location int32, count int32, value *float32) {
+	gl.Uniform3fv(location, count, value)
+}
+
+func (goGLContext) Uniform4fv(location int32, count int32, value *float32) {
+	gl.Uniform4fv(location, count, value)
+}
+
+func (goGLContext) UniformMatrix2fv(location int32, count int32, transpose bool, value *float32) {
+	gl.UniformMatrix2fv(location, count, transpose, value)
+}
+
+func (goGLContext) UniformMatrix3fv(location int32, count int32, transpose bool, value *float32) {
+	gl.UniformMatrix3fv(location, count, transpose, value)
+}
+
+func (goGLContext) UniformMatrix4fv(location int32, count int32, transpose bool, value *float32) {
+	gl.UniformMatrix4fv(location, count, transpose, value)
+}
+
+func (goGLContext) Enable(cap uint32) {
+	gl.Enable(cap)
+}
+
+func (goGLContext) Disable(cap uint32) {
+	gl.Disable(cap)
+}
+
+func (goGLContext) IsEnabled(cap uint32) bool {
+	return gl.IsEnabled(cap)
+}
+
+func (goGLContext) GetIntegerv(pname uint32, data *int32) {
+	gl.GetIntegerv(pname, data)
+}
+
+func (goGLContext) GetBooleanv(pname uint32, data *bool) {
+	gl.GetBooleanv(pname, data)
+}
+
+func (goGLContext) BlendFunc(sfactor uint32, dfactor uint32) {
+	gl.BlendFunc(sfactor, dfactor)
+}
+
+func (goGLContext) BlendFuncSeparate(sfactorRGB uint32, dfactorRGB uint32, sfactorAlpha uint32, dfactorAlpha uint32) {
+	gl.BlendFuncSeparate(sfactorRGB, dfactorRGB, sfactorAlpha, dfactorAlpha)
+}
+
+func (goGLContext) DepthMask(flag bool) {
+	gl.DepthMask(flag)
+}
+
+func (goGLContext) CullFace(mode uint32) {
+	gl.CullFace(mode)
+}
+
+func (goGLContext) FrontFace(mode uint32) {
+	gl.FrontFace(mode)
+}
+
+func (goGLContext) GetError() uint32 {
+	return gl.GetError()
+}
+
+func (goGLContext) Viewport(x int32, y int32, width int32, height int32) {
+	gl.Viewport(x, y, width, height)
+}
+
+func (goGLContext) ClearColor(red float32, green float32, blue float32, alpha float32) {
+	gl.ClearColor(red, green, blue, alpha)
+}
+
+func (goGLContext) Clear(mask uint32) {
+	gl.Clear(mask)
+}
+
+func (goGLContext) GetStringi(name uint32, index uint32) *uint8 {
+	return gl.GetStringi(name, index)
+}
+
+func (goGLContext) DebugMessageCallback(callback gl.DebugProc, userParam unsafe.Pointer) {
+	gl.DebugMessageCallback(callback, userParam)
+}
+
+func (goGLContext) GenFramebuffers(n int32, framebuffers *uint32) {
+	gl.GenFramebuffers(n, framebuffers)
+}
+
+func (goGLContext) DeleteFramebuffers(n int32, framebuffers *uint32) {
+	gl.DeleteFramebuffers(n, framebuffers)
+}
+
+func (goGLContext) BindFramebuffer(target uint32, framebuffer uint32) {
+	gl.BindFramebuffer(target, framebuffer)
+}
+
+func (goGLContext) FramebufferTexture2D(target uint32, attachment uint32, textarget uint32, texture uint32, level int32) {
+	gl.FramebufferTexture2D(target, attachment, textarget, texture, level)
+}
+
+func (goGLContext) FramebufferRenderbuffer(target uint32, attachment uint32, renderbuffertarget uint32, renderbuffer uint32) {
+	gl.FramebufferRenderbuffer(target, attachment, renderbuffertarget, renderbuffer)
+}
+
+func (goGLContext) CheckFramebufferStatus(target uint32) uint32 {
+	return gl.CheckFramebufferStatus(target)
+}
+
+func (goGLContext) GenRenderbuffers(n int32, renderbuffers *uint32) {
+	gl.GenRenderbuffers(n, renderbuffers)
+}
+
+func (goGLContext) DeleteRenderbuffers(n int32, renderbuffers *uint32) {
+	gl.DeleteRenderbuffers(n, renderbuffers)
+}
+
+func (goGLContext) BindRenderbuffer(target uint32, renderbuffer uint32) {
+	gl.BindRenderbuffer(target, renderbuffer)
+}
+
+func (goGLContext) RenderbufferStorage(target uint32, internalformat uint32, width int32, height int32) {
+	gl.RenderbufferStorage(target, internalformat, width, height)
+}
+
+func (goGLContext) ReadPixels(x int32, y int32, width int32, height int32, format uint32, xtype uint32, pixels unsafe.Pointer) {
+	gl.ReadPixels(x, y, width, height, format, xtype, pixels)
+}
+
+func (goGLContext) GenTextures(n int32, textures *uint32) {
+	gl.GenTextures(n, textures)
+}
+
+func (goGLContext) DeleteTextures(n int32, textures *uint32) {
+	gl.DeleteTextures(n, textures)
+}
+
+func (goGLContext) ActiveTexture(texture uint32) {
+	gl.ActiveTexture(texture)
+}
+
+func (goGLContext) BindTexture(target uint32, texture uint32) {
+	gl.BindTexture(target, texture)
+}
+
+func (goGLContext) TexParameteri(target uint32, pname uint32, param int32) {
+	gl.TexParameteri(target, pname, param)
+}
+
+func (goGLContext) PixelStorei(pname uint32, param int32) {
+	gl.PixelStorei(pname, param)
+}
+
+func (goGLContext) TexImage2D(target uint32, level int32, internalformat int32, width int32, height int32, border int32, format uint32, xtype uint32, pixels unsafe.Pointer) {
+	gl.TexImage2D(target, level, internalformat, width, height, border, format, xtype, pixels)
+}
+
+func (goGLContext) TexSubImage2D(target uint32, level int32, xoffset int32, yoffset int32, width int32, height int32, format uint32, xtype uint32, pixels unsafe.Pointer) {
+	gl.TexSubImage2D(target, level, xoffset, yoffset, width, height, format, xtype, pixels)
+}
+
+func (goGLContext) TexImage3D(target uint32, level int32, internalformat int32, width int32, height int32, depth int32, border int32, format uint32, xtype uint32, pixels unsafe.Pointer) {
+	gl.TexImage3D(target, level, internalformat, width, height, depth, border, format, xtype, pixels)
+}
+
+func (goGLContext) TexSubImage3D(target uint32, level int32, xoffset int32, yoffset int32, zoffset int32, width int32, height int32, depth int32, format uint32, xtype uint32, pixels unsafe.Pointer) {
+	gl.TexSubImage3D(target, level, xoffset, yoffset, zoffset, width, height, depth, format, xtype, pixels)
+}
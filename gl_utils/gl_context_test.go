@@ -0,0 +1,527 @@
+package gl_utils
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"unsafe"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// recordingGLContext a glContext which records the calls instead of executing them. It hands out increasing IDs
+// for the objects created, reports every shader as compiled and linked, and finds the uniforms used by the
+// sources attached to a program
+type recordingGLContext struct {
+	calls     []string
+	nextID    uint32
+	sources   map[uint32]string
+	attached  map[uint32][]uint32
+	locations map[string]int32
+	enabled   map[uint32]bool
+
+	// Vertex attributes set up in each vertex array
+	attributes       map[uint32]map[uint32]*recordedAttribute
+	boundVertexArray uint32
+	boundArrayBuffer uint32
+
+	// Values of the 4x4 matrices uploaded, in order
+	matrices []recordedMatrix
+	// Uniforms reported by GetActiveUniform, with the first value returned when they are read
+	activeUniforms []recordedUniform
+
+	// Framebuffer bound for drawing and reading, and the viewport
+	boundFramebuffer uint32
+	viewport         [4]int32
+	// Memory handed out by MapBufferRange
+	mappedBuffer []byte
+}
+
+// recordedUniform an active uniform of a program and the first value it holds, of the Go type matching its GL type
+type recordedUniform struct {
+	name        string
+	uniformType uint32
+	value       interface{}
+}
+
+// recordedMatrix a 4x4 matrix uploaded to a uniform
+type recordedMatrix struct {
+	location int32
+	value    mgl32.Mat4
+}
+
+// recordedAttribute the state of a vertex attribute of a vertex array
+type recordedAttribute struct {
+	enabled bool
+	buffer  uint32
+	size    int32
+}
+
+// useRecordingGL replaces glc with a recorder for the duration of the test. The shader cache is cleared, since the
+// programs compiled before don't exist in the recorder
+func useRecordingGL(t *testing.T) *recordingGLContext {
+	r := &recordingGLContext{
+		sources:   make(map[uint32]string),
+		attached:  make(map[uint32][]uint32),
+		locations: make(map[string]int32),
+		enabled:   make(map[uint32]bool),
+
+		attributes: make(map[uint32]map[uint32]*recordedAttribute),
+	}
+	previous := glc
+	glc = r
+	ClearShaderCache()
+	t.Cleanup(func() {
+		glc = previous
+		ClearShaderCache()
+	})
+	return r
+}
+
+func (r *recordingGLContext) record(name string, args ...interface{}) {
+	values := make([]string, len(args))
+	for i, arg := range args {
+		values[i] = fmt.Sprint(arg)
+	}
+	r.calls = append(r.calls, name+"("+strings.Join(values, ", ")+")")
+}
+
+func (r *recordingGLContext) newID() uint32 {
+	r.nextID++
+	return r.nextID
+}
+
+// reset forgets the calls and the matrices recorded so far
+func (r *recordingGLContext) reset() {
+	r.calls = nil
+	r.matrices = nil
+}
+
+// without returns the calls recorded, skipping the ones to the functions named
+func (r *recordingGLContext) without(names ...string) []string {
+	var calls []string
+	for _, call := range r.calls {
+		skip := false
+		for _, name := range names {
+			if strings.HasPrefix(call, name+"(") {
+				skip = true
+			}
+		}
+		if !skip {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// attribute returns the state of a vertex attribute of the bound vertex array
+func (r *recordingGLContext) attribute(index uint32) *recordedAttribute {
+	if r.attributes[r.boundVertexArray] == nil {
+		r.attributes[r.boundVertexArray] = make(map[uint32]*recordedAttribute)
+	}
+	if r.attributes[r.boundVertexArray][index] == nil {
+		r.attributes[r.boundVertexArray][index] = &recordedAttribute{}
+	}
+	return r.attributes[r.boundVertexArray][index]
+}
+
+// activeUniform returns whether one of the shaders attached to the program declares the uniform and uses it, like
+// the GLSL compiler which drops the unused ones. Array elements and struct fields (e.g. 'lights[0].color') are looked
+// up by the name of the array
+func (r *recordingGLContext) activeUniform(program uint32, name string) bool {
+	if end := strings.IndexAny(name, "[."); end >= 0 {
+		name = name[:end]
+	}
+	word := regexp.MustCompile(`\b` + regexp.QuoteMeta(name) + `\b`)
+	declared, used := false, false
+	for _, shader := range r.attached[program] {
+		for _, line := range strings.Split(r.sources[shader], "\n") {
+			if !word.MatchString(line) {
+				continue
+			}
+			if strings.HasPrefix(strings.TrimSpace(line), "uniform ") {
+				declared = true
+			} else {
+				used = true
+			}
+		}
+	}
+	return declared && used
+}
+
+func (r *recordingGLContext) GenVertexArrays(n int32, arrays *uint32) {
+	*arrays = r.newID()
+	r.record("GenVertexArrays", n, *arrays)
+}
+func (r *recordingGLContext) BindVertexArray(array uint32) {
+	r.boundVertexArray = array
+	r.record("BindVertexArray", array)
+}
+func (r *recordingGLContext) DeleteVertexArrays(n int32, arrays *uint32) {
+	r.record("DeleteVertexArrays", n, *arrays)
+}
+func (r *recordingGLContext) GenBuffers(n int32, buffers *uint32) {
+	*buffers = r.newID()
+	r.record("GenBuffers", n, *buffers)
+}
+func (r *recordingGLContext) DeleteBuffers(n int32, buffers *uint32) {
+	r.record("DeleteBuffers", n, *buffers)
+}
+func (r *recordingGLContext) BindBuffer(target uint32, buffer uint32) {
+	if target == gl.ARRAY_BUFFER {
+		r.boundArrayBuffer = buffer
+	}
+	r.record("BindBuffer", target, buffer)
+}
+func (r *recordingGLContext) BufferData(target uint32, size int, data unsafe.Pointer, usage uint32) {
+	r.record("BufferData", target, size, usage)
+}
+func (r *recordingGLContext) BufferSubData(target uint32, offset int, size int, data unsafe.Pointer) {
+	r.record("BufferSubData", target, offset, size)
+}
+func (r *recordingGLContext) EnableVertexAttribArray(index uint32) {
+	r.attribute(index).enabled = true
+	r.record("EnableVertexAttribArray", index)
+}
+func (r *recordingGLContext) VertexAttribPointer(index uint32, size int32, xtype uint32, normalized bool, stride int32, pointer unsafe.Pointer) {
+	attribute := r.attribute(index)
+	attribute.buffer = r.boundArrayBuffer
+	attribute.size = size
+	r.record("VertexAttribPointer", index, size, xtype, normalized, stride, uintptr(pointer))
+}
+func (r *recordingGLContext) DrawArrays(mode uint32, first int32, count int32) {
+	r.record("DrawArrays", mode, first, count)
+}
+func (r *recordingGLContext) VertexAttribDivisor(index uint32, divisor uint32) {
+	r.record("VertexAttribDivisor", index, divisor)
+}
+func (r *recordingGLContext) DrawArraysInstanced(mode uint32, first int32, count int32, instancecount int32) {
+	r.record("DrawArraysInstanced", mode, first, count, instancecount)
+}
+func (r *recordingGLContext) MapBufferRange(target uint32, offset int, length int, access uint32) unsafe.Pointer {
+	r.record("MapBufferRange", target, offset, length, access)
+	r.mappedBuffer = make([]byte, length)
+	return unsafe.Pointer(&r.mappedBuffer[0])
+}
+func (r *recordingGLContext) UnmapBuffer(target uint32) bool {
+	r.record("UnmapBuffer", target)
+	return true
+}
+
+func (r *recordingGLContext) CreateProgram() uint32 {
+	program := r.newID()
+	r.record("CreateProgram", program)
+	return program
+}
+func (r *recordingGLContext) DeleteProgram(program uint32) { r.record("DeleteProgram", program) }
+func (r *recordingGLContext) CreateShader(xtype uint32) uint32 {
+	shader := r.newID()
+	r.record("CreateShader", xtype, shader)
+	return shader
+}
+func (r *recordingGLContext) ShaderSource(shader uint32, count int32, xstring **uint8, length *int32) {
+	r.sources[shader] = gl.GoStr(*xstring)
+	r.record("ShaderSource", shader)
+}
+func (r *recordingGLContext) CompileShader(shader uint32) { r.record("CompileShader", shader) }
+func (r *recordingGLContext) GetShaderiv(shader uint32, pname uint32, params *int32) {
+	*params = 0
+	if pname == gl.COMPILE_STATUS {
+		*params = gl.TRUE
+	}
+}
+func (r *recordingGLContext) GetShaderInfoLog(shader uint32, bufSize int32, length *int32, infoLog *uint8) {
+}
+func (r *recordingGLContext) AttachShader(program uint32, shader uint32) {
+	r.attached[program] = append(r.attached[program], shader)
+	r.record("AttachShader", program, shader)
+}
+func (r *recordingGLContext) LinkProgram(program uint32)     { r.record("LinkProgram", program) }
+func (r *recordingGLContext) ValidateProgram(program uint32) { r.record("ValidateProgram", program) }
+func (r *recordingGLContext) GetProgramiv(program uint32, pname uint32, params *int32) {
+	*params = 0
+	switch pname {
+	case gl.LINK_STATUS, gl.VALIDATE_STATUS:
+		*params = gl.TRUE
+	case gl.ACTIVE_UNIFORMS:
+		*params = int32(len(r.activeUniforms))
+	case gl.ACTIVE_UNIFORM_MAX_LENGTH:
+		for _, uniform := range r.activeUniforms {
+			if int32(len(uniform.name)+1) > *params {
+				*params = int32(len(uniform.name) + 1)
+			}
+		}
+	}
+}
+func (r *recordingGLContext) GetProgramInfoLog(program uint32, bufSize int32, length *int32, infoLog *uint8) {
+}
+func (r *recordingGLContext) GetAttribLocation(program uint32, name *uint8) int32 { return -1 }
+func (r *recordingGLContext) GetActiveAttrib(program uint32, index uint32, bufSize int32, length *int32, size *int32, xtype *uint32, name *uint8) {
+}
+func (r *recordingGLContext) GetActiveUniform(program uint32, index uint32, bufSize int32, length *int32, size *int32, xtype *uint32, name *uint8) {
+	uniform := r.activeUniforms[index]
+	buffer := (*[1 << 16]uint8)(unsafe.Pointer(name))[:bufSize:bufSize]
+	*length = int32(copy(buffer, uniform.name))
+	*size = 1
+	*xtype = uniform.uniformType
+}
+func (r *recordingGLContext) UseProgram(program uint32) { r.record("UseProgram", program) }
+
+func (r *recordingGLContext) GetUniformLocation(program uint32, name *uint8) int32 {
+	uniform := gl.GoStr(name)
+	r.record("GetUniformLocation", program, uniform)
+	if !r.activeUniform(program, uniform) {
+		return -1
+	}
+	key := fmt.Sprintf("%d/%s", program, uniform)
+	if _, found := r.locations[key]; !found {
+		r.locations[key] = int32(len(r.locations))
+	}
+	return r.locations[key]
+}
+func (r *recordingGLContext) GetUniformfv(program uint32, location int32, params *float32) {
+	*params = r.uniformValue(location).(float32)
+}
+func (r *recordingGLContext) GetUniformiv(program uint32, location int32, params *int32) {
+	*params = r.uniformValue(location).(int32)
+}
+func (r *recordingGLContext) GetUniformuiv(program uint32, location int32, params *uint32) {
+	*params = r.uniformValue(location).(uint32)
+}
+func (r *recordingGLContext) GetUniformdv(program uint32, location int32, params *float64) {
+	*params = r.uniformValue(location).(float64)
+}
+
+// uniformValue returns the first value of the active uniform at the location passed
+func (r *recordingGLContext) uniformValue(location int32) interface{} {
+	for key, uniformLocation := range r.locations {
+		if uniformLocation != location {
+			continue
+		}
+		for _, uniform := range r.activeUniforms {
+			if strings.HasSuffix(key, "/"+uniform.name) {
+				return uniform.value
+			}
+		}
+	}
+	panic(fmt.Sprintf("no active uniform at location %d", location))
+}
+func (r *recordingGLContext) Uniform1f(location int32, v0 float32) {
+	r.record("Uniform1f", location, v0)
+}
+func (r *recordingGLContext) Uniform1iv(location int32, count int32, value *int32) {
+	r.record("Uniform1iv", location, count, *value)
+}
+func (r *recordingGLContext) Uniform1fv(location int32, count int32, value *float32) {
+	r.record("Uniform1fv", location, count, *value)
+}
+func (r *recordingGLContext) Uniform2fv(location int32, count int32, value *float32) {
+	r.record("Uniform2fv", location, count)
+}
+func (r *recordingGLContext) Uniform3fv(location int32, count int32, value *float32) {
+	r.record("Uniform3fv", location, count)
+}
+func (r *recordingGLContext) Uniform4fv(location int32, count int32, value *float32) {
+	r.record("Uniform4fv", location, count)
+}
+func (r *recordingGLContext) UniformMatrix2fv(location int32, count int32, transpose bool, value *float32) {
+	r.record("UniformMatrix2fv", location, count)
+}
+func (r *recordingGLContext) UniformMatrix3fv(location int32, count int32, transpose bool, value *float32) {
+	r.record("UniformMatrix3fv", location, count)
+}
+func (r *recordingGLContext) UniformMatrix4fv(location int32, count int32, transpose bool, value *float32) {
+	r.record("UniformMatrix4fv", location, count)
+	r.matrices = append(r.matrices, recordedMatrix{location, *(*mgl32.Mat4)(unsafe.Pointer(value))})
+}
+
+func (r *recordingGLContext) Enable(cap uint32) {
+	r.enabled[cap] = true
+	r.record("Enable", cap)
+}
+func (r *recordingGLContext) Disable(cap uint32) {
+	r.enabled[cap] = false
+	r.record("Disable", cap)
+}
+func (r *recordingGLContext) IsEnabled(cap uint32) bool { return r.enabled[cap] }
+func (r *recordingGLContext) GetIntegerv(pname uint32, data *int32) {
+	*data = 0
+	switch pname {
+	case gl.UNPACK_ALIGNMENT:
+		*data = 4
+	case gl.FRAMEBUFFER_BINDING, gl.READ_FRAMEBUFFER_BINDING:
+		*data = int32(r.boundFramebuffer)
+	case gl.VIEWPORT:
+		*(*[4]int32)(unsafe.Pointer(data)) = r.viewport
+	}
+}
+func (r *recordingGLContext) GetBooleanv(pname uint32, data *bool) { *data = true }
+func (r *recordingGLContext) BlendFunc(sfactor uint32, dfactor uint32) {
+	r.record("BlendFunc", sfactor, dfactor)
+}
+func (r *recordingGLContext) BlendFuncSeparate(sfactorRGB uint32, dfactorRGB uint32, sfactorAlpha uint32, dfactorAlpha uint32) {
+	r.record("BlendFuncSeparate", sfactorRGB, dfactorRGB, sfactorAlpha, dfactorAlpha)
+}
+func (r *recordingGLContext) DepthMask(flag bool) { r.record("DepthMask", flag) }
+func (r *recordingGLContext) CullFace(mode uint32) {
+	r.record("CullFace", mode)
+}
+func (r *recordingGLContext) FrontFace(mode uint32) { r.record("FrontFace", mode) }
+func (r *recordingGLContext) Viewport(x int32, y int32, width int32, height int32) {
+	r.viewport = [4]int32{x, y, width, height}
+	r.record("Viewport", x, y, width, height)
+}
+func (r *recordingGLContext) ClearColor(red float32, green float32, blue float32, alpha float32) {
+	r.record("ClearColor", red, green, blue, alpha)
+}
+func (r *recordingGLContext) Clear(mask uint32) { r.record("Clear", mask) }
+
+func (r *recordingGLContext) GetError() uint32 { return gl.NO_ERROR }
+func (r *recordingGLContext) GetStringi(name uint32, index uint32) *uint8 {
+	return gl.Str("\x00")
+}
+func (r *recordingGLContext) DebugMessageCallback(callback gl.DebugProc, userParam unsafe.Pointer) {
+	r.record("DebugMessageCallback")
+}
+
+func (r *recordingGLContext) GenFramebuffers(n int32, framebuffers *uint32) {
+	*framebuffers = r.newID()
+	r.record("GenFramebuffers", n, *framebuffers)
+}
+func (r *recordingGLContext) DeleteFramebuffers(n int32, framebuffers *uint32) {
+	r.record("DeleteFramebuffers", n, *framebuffers)
+}
+func (r *recordingGLContext) BindFramebuffer(target uint32, framebuffer uint32) {
+	r.boundFramebuffer = framebuffer
+	r.record("BindFramebuffer", target, framebuffer)
+}
+func (r *recordingGLContext) FramebufferTexture2D(target uint32, attachment uint32, textarget uint32, texture uint32, level int32) {
+	r.record("FramebufferTexture2D", target, attachment, textarget, texture, level)
+}
+func (r *recordingGLContext) FramebufferRenderbuffer(target uint32, attachment uint32, renderbuffertarget uint32, renderbuffer uint32) {
+	r.record("FramebufferRenderbuffer", target, attachment, renderbuffertarget, renderbuffer)
+}
+func (r *recordingGLContext) CheckFramebufferStatus(target uint32) uint32 {
+	return gl.FRAMEBUFFER_COMPLETE
+}
+func (r *recordingGLContext) GenRenderbuffers(n int32, renderbuffers *uint32) {
+	*renderbuffers = r.newID()
+	r.record("GenRenderbuffers", n, *renderbuffers)
+}
+func (r *recordingGLContext) DeleteRenderbuffers(n int32, renderbuffers *uint32) {
+	r.record("DeleteRenderbuffers", n, *renderbuffers)
+}
+func (r *recordingGLContext) BindRenderbuffer(target uint32, renderbuffer uint32) {
+	r.record("BindRenderbuffer", target, renderbuffer)
+}
+func (r *recordingGLContext) RenderbufferStorage(target uint32, internalformat uint32, width int32, height int32) {
+	r.record("RenderbufferStorage", target, internalformat, width, height)
+}
+func (r *recordingGLContext) ReadPixels(x int32, y int32, width int32, height int32, format uint32, xtype uint32, pixels unsafe.Pointer) {
+	r.record("ReadPixels", x, y, width, height, format, xtype)
+}
+
+func (r *recordingGLContext) GenTextures(n int32, textures *uint32) {
+	*textures = r.newID()
+	r.record("GenTextures", n, *textures)
+}
+func (r *recordingGLContext) DeleteTextures(n int32, textures *uint32) {
+	r.record("DeleteTextures", n, *textures)
+}
+func (r *recordingGLContext) ActiveTexture(texture uint32) { r.record("ActiveTexture", texture) }
+func (r *recordingGLContext) BindTexture(target uint32, texture uint32) {
+	r.record("BindTexture", target, texture)
+}
+func (r *recordingGLContext) TexParameteri(target uint32, pname uint32, param int32) {
+	r.record("TexParameteri", target, pname, param)
+}
+func (r *recordingGLContext) PixelStorei(pname uint32, param int32) {
+	r.record("PixelStorei", pname, param)
+}
+func (r *recordingGLContext) TexImage2D(target uint32, level int32, internalformat int32, width int32, height int32, border int32, format uint32, xtype uint32, pixels unsafe.Pointer) {
+	r.record("TexImage2D", target, level, internalformat, width, height, border, format, xtype)
+}
+func (r *recordingGLContext) TexSubImage2D(target uint32, level int32, xoffset int32, yoffset int32, width int32, height int32, format uint32, xtype uint32, pixels unsafe.Pointer) {
+	r.record("TexSubImage2D", target, level, xoffset, yoffset, width, height, format, xtype)
+}
+func (r *recordingGLContext) TexImage3D(target uint32, level int32, internalformat int32, width int32, height int32, depth int32, border int32, format uint32, xtype uint32, pixels unsafe.Pointer) {
+	r.record("TexImage3D", target, level, internalformat, width, height, depth, border, format, xtype)
+}
+func (r *recordingGLContext) TexSubImage3D(target uint32, level int32, xoffset int32, yoffset int32, zoffset int32, width int32, height int32, depth int32, format uint32, xtype uint32, pixels unsafe.Pointer) {
+	r.record("TexSubImage3D", target, level, xoffset, yoffset, zoffset, width, height, depth, format, xtype)
+}
+
+func TestSetVerticesCallSequence(t *testing.T) {
+	r := useRecordingGL(t)
+	p := newPrimitive2D(mgl32.Vec3{}, mgl32.Vec2{1, 1}, SharedShaderProgram(VertexShaderBase, "", FragmentShaderSolidColor))
+	r.reset()
+
+	p.SetVertices([]float32{0, 0, 1, 0, 0, 1})
+
+	expected := []string{
+		fmt.Sprintf("BindVertexArray(%d)", p.vaoId),
+		fmt.Sprintf("GenBuffers(1, %d)", p.vboVertices),
+		fmt.Sprintf("BindBuffer(%d, %d)", gl.ARRAY_BUFFER, p.vboVertices),
+		fmt.Sprintf("BufferData(%d, 24, %d)", gl.ARRAY_BUFFER, gl.STATIC_DRAW),
+		"EnableVertexAttribArray(0)",
+		fmt.Sprintf("VertexAttribPointer(0, 2, %d, false, 0, 0)", gl.FLOAT),
+		"BindVertexArray(0)",
+	}
+	if !reflect.DeepEqual(r.calls, expected) {
+		t.Errorf("SetVertices calls:\n%s\nexpected:\n%s", strings.Join(r.calls, "\n"), strings.Join(expected, "\n"))
+	}
+	if p.ArraySize() != 3 {
+		t.Errorf("ArraySize() = %d, expected 3", p.ArraySize())
+	}
+
+	// A second upload reuses the buffer
+	r.reset()
+	p.SetVertices([]float32{0, 0, 1, 0})
+	for _, call := range r.calls {
+		if strings.HasPrefix(call, "GenBuffers(") {
+			t.Errorf("the vertex buffer has been created again: %s", call)
+		}
+	}
+}
+
+func TestDrawCallSequence(t *testing.T) {
+	r := useRecordingGL(t)
+	shader := SharedShaderProgram(VertexShaderBase, "", FragmentShaderSolidColor)
+	p := newPrimitive2D(mgl32.Vec3{}, mgl32.Vec2{1, 1}, shader)
+	p.SetArrayMode(gl.TRIANGLES)
+	p.SetVertices([]float32{0, 0, 1, 0, 0, 1})
+	projection := mgl32.Ortho2D(0, 100, 100, 0)
+	r.reset()
+
+	p.Draw(&projection)
+
+	expected := []string{
+		fmt.Sprintf("UseProgram(%d)", shader.ID()),
+		fmt.Sprintf("UniformMatrix4fv(%d, 1)", shader.GetUniform("projection")),
+		fmt.Sprintf("Uniform4fv(%d, 1)", shader.GetUniform("color")),
+		fmt.Sprintf("UniformMatrix4fv(%d, 1)", shader.GetUniform("model")),
+		fmt.Sprintf("BindVertexArray(%d)", p.vaoId),
+		fmt.Sprintf("DrawArrays(%d, 0, 3)", gl.TRIANGLES),
+	}
+	calls := r.without("GetUniformLocation")
+	if !reflect.DeepEqual(calls, expected) {
+		t.Errorf("Draw calls:\n%s\nexpected:\n%s", strings.Join(calls, "\n"), strings.Join(expected, "\n"))
+	}
+}
+
+func TestDrawRestoresBlendState(t *testing.T) {
+	r := useRecordingGL(t)
+	p := newPrimitive2D(mgl32.Vec3{}, mgl32.Vec2{1, 1}, SharedShaderProgram(VertexShaderBase, "", FragmentShaderSolidColor))
+	p.SetVertices([]float32{0, 0, 1, 0, 0, 1})
+	p.SetBlendMode(BlendAdditive)
+	projection := mgl32.Ident4()
+
+	p.Draw(&projection)
+
+	if r.enabled[gl.BLEND] {
+		t.Error("blending is still enabled after the draw")
+	}
+}
@@ -0,0 +1,77 @@
+package gl_utils
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unsafe"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+var glErrorNames = map[uint32]string{
+	gl.INVALID_ENUM:                  "GL_INVALID_ENUM",
+	gl.INVALID_VALUE:                 "GL_INVALID_VALUE",
+	gl.INVALID_OPERATION:             "GL_INVALID_OPERATION",
+	gl.STACK_OVERFLOW:                "GL_STACK_OVERFLOW",
+	gl.STACK_UNDERFLOW:               "GL_STACK_UNDERFLOW",
+	gl.OUT_OF_MEMORY:                 "GL_OUT_OF_MEMORY",
+	gl.INVALID_FRAMEBUFFER_OPERATION: "GL_INVALID_FRAMEBUFFER_OPERATION",
+}
+
+// debugDraw enables the error checks after each draw call
+var debugDraw bool
+
+// SetDebugDraw enables or disables the OpenGL error checks after each draw call of the primitives. Errors are
+// printed on the standard output. It slows down the rendering, use it only while debugging
+func SetDebugDraw(enabled bool) {
+	debugDraw = enabled
+}
+
+// CheckGLError returns all the OpenGL errors raised since the last check, or nil if there are none.
+// The label is added to the error message to identify where the check happened
+func CheckGLError(label string) error {
+	var names []string
+	for code := glc.GetError(); code != gl.NO_ERROR; code = glc.GetError() {
+		name, found := glErrorNames[code]
+		if !found {
+			name = fmt.Sprintf("0x%04X", code)
+		}
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%s: %s", label, strings.Join(names, ", "))
+}
+
+// EnableDebugOutput makes the driver report errors and warnings as soon as they happen. It requires OpenGL 4.3 or the
+// KHR_debug extension, which are not available on MacOS
+func EnableDebugOutput() error {
+	var major, minor int32
+	glc.GetIntegerv(gl.MAJOR_VERSION, &major)
+	glc.GetIntegerv(gl.MINOR_VERSION, &minor)
+	if major < 4 || (major == 4 && minor < 3) {
+		if !hasGLExtension("GL_KHR_debug") {
+			return errors.New("debug output requires OpenGL 4.3 or the GL_KHR_debug extension")
+		}
+	}
+
+	glc.Enable(gl.DEBUG_OUTPUT)
+	glc.Enable(gl.DEBUG_OUTPUT_SYNCHRONOUS)
+	glc.DebugMessageCallback(func(source uint32, gltype uint32, id uint32, severity uint32, length int32, message string, userParam unsafe.Pointer) {
+		fmt.Printf("GL debug [source 0x%X, type 0x%X, id %d, severity 0x%X]: %s\n", source, gltype, id, severity, message)
+	}, nil)
+	return nil
+}
+
+func hasGLExtension(name string) bool {
+	var numExtensions int32
+	glc.GetIntegerv(gl.NUM_EXTENSIONS, &numExtensions)
+	for i := int32(0); i < numExtensions; i++ {
+		if gl.GoStr(glc.GetStringi(gl.EXTENSIONS, uint32(i))) == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,36 @@
+package gl_utils
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Updatable an object advancing over time, updated once per frame by the game loop with the seconds elapsed
+type Updatable interface {
+	Update(dt float32)
+}
+
+// Drawable an object drawn with a projection matrix
+type Drawable interface {
+	Draw(projectionMatrix *mgl32.Mat4)
+}
+
+// The types of the package meant to be used in the game loop
+var (
+	_ Updatable = (*Animation)(nil)
+	_ Updatable = (*Camera2D)(nil)
+	_ Updatable = (*ParticleSystem)(nil)
+	_ Updatable = (*Tween)(nil)
+	_ Updatable = (*Tweener)(nil)
+
+	_ Drawable = (*Primitive)(nil)
+	_ Drawable = (*Primitive2D)(nil)
+	_ Drawable = (*PrimitiveGroup)(nil)
+	_ Drawable = (*DrawList)(nil)
+	_ Drawable = (*ParticleSystem)(nil)
+	_ Drawable = (*TextPrimitive)(nil)
+	_ Drawable = (*ThickPolylinePrimitive)(nil)
+	_ Drawable = (*ScrollingBackground)(nil)
+	_ Drawable = (*SmoothCirclePrimitive)(nil)
+	_ Drawable = (*SmoothRoundedRectPrimitive)(nil)
+	_ Drawable = (*PlotPrimitive)(nil)
+)
@@ -0,0 +1,112 @@
+package gl_utils
+
+import (
+	"fmt"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// MaxLights2D is the maximum number of lights supported by FragmentShaderNormalMapped
+const MaxLights2D = 16
+
+// Light2D a point light used by FragmentShaderNormalMapped
+type Light2D struct {
+	// Position of the light in world space. Z is the height of the light above the XY plane
+	Position mgl32.Vec3
+	Color    Color
+	// Radius is the distance at which the light doesn't have any effect anymore
+	Radius    float32
+	Intensity float32
+}
+
+// SetLights sets the lights affecting a primitive drawn with FragmentShaderNormalMapped. The normal map is expected
+// in the "normal_map" sampler (see SetTextures). Only the first MaxLights2D lights are used
+func (p *Primitive2D) SetLights(lights []Light2D) {
+	if len(lights) > MaxLights2D {
+		fmt.Printf("Warning: only %d lights out of %d are used\n", MaxLights2D, len(lights))
+		lights = lights[:MaxLights2D]
+	}
+	p.lights = lights
+}
+
+// Lights returns the lights affecting the primitive
+func (p *Primitive2D) Lights() []Light2D {
+	return p.lights
+}
+
+// SetAmbientLight sets the light applied to the whole primitive when drawn with FragmentShaderNormalMapped
+func (p *Primitive2D) SetAmbientLight(ambient Color) {
+	p.ambientLight = ambient
+}
+
+func (p *Primitive2D) setLightUniforms() {
+	numLights := int32(len(p.lights))
+	p.shaderProgram.SetUniform("num_lights", &numLights)
+	p.shaderProgram.SetUniform("ambient_light", &p.ambientLight)
+	for i := range p.lights {
+		light := &p.lights[i]
+		p.shaderProgram.SetUniform(fmt.Sprintf("light_positions[%d]", i), &light.Position)
+		p.shaderProgram.SetUniform(fmt.Sprintf("light_colors[%d]", i), &light.Color)
+		p.shaderProgram.SetUniform(fmt.Sprintf("light_radii[%d]", i), &light.Radius)
+		p.shaderProgram.SetUniform(fmt.Sprintf("light_intensities[%d]", i), &light.Intensity)
+	}
+}
+
+const (
+	// VertexShaderWorldPosition passes the world position of each vertex to the fragment shader
+	VertexShaderWorldPosition = `
+        #version 410 core
+
+        uniform mat4 model;
+        uniform mat4 projection;
+
+        layout(location=0) in vec2 vertex;
+        layout(location=1) in vec2 uv;
+
+        out vec2 uv_out;
+        out vec3 world_position;
+
+        void main() {
+            vec4 vertex_world = model * vec4(vertex, 0, 1);
+            gl_Position = projection * vertex_world;
+            uv_out = uv;
+            world_position = vertex_world.xyz;
+        }
+        ` + "\x00"
+
+	// FragmentShaderNormalMapped lights a texture using a normal map and a set of point lights.
+	// It must be used with VertexShaderWorldPosition
+	FragmentShaderNormalMapped = `
+        #version 410 core
+
+        #define MAX_LIGHTS 16
+
+        in vec2 uv_out;
+        in vec3 world_position;
+        out vec4 out_color;
+
+        uniform sampler2D tex;
+        uniform sampler2D normal_map;
+        uniform vec4 ambient_light;
+        uniform int num_lights;
+        uniform vec3 light_positions[MAX_LIGHTS];
+        uniform vec4 light_colors[MAX_LIGHTS];
+        uniform float light_radii[MAX_LIGHTS];
+        uniform float light_intensities[MAX_LIGHTS];
+
+        void main() {
+            vec4 base = texture(tex, uv_out);
+            vec3 normal = normalize(texture(normal_map, uv_out).rgb * 2.0 - 1.0);
+
+            vec3 light = ambient_light.rgb;
+            for (int i = 0; i < num_lights; i++) {
+                vec3 to_light = light_positions[i] - world_position;
+                float distance = length(to_light.xy);
+                float attenuation = clamp(1.0 - distance / light_radii[i], 0.0, 1.0);
+                float diffuse = max(dot(normal, normalize(to_light)), 0.0);
+                light += light_colors[i].rgb * light_intensities[i] * diffuse * attenuation * attenuation;
+            }
+            out_color = vec4(base.rgb * light, base.a);
+        }
+        ` + "\x00"
+)
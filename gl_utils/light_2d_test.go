@@ -0,0 +1,44 @@
+package gl_utils
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestUnlitPrimitiveResetsSharedLights(t *testing.T) {
+	r := useRecordingGL(t)
+	texture := &Texture{width: 1, height: 1}
+	newLitQuad := func() *Primitive2D {
+		shader := SharedShaderProgram(VertexShaderWorldPosition, "", FragmentShaderNormalMapped)
+		quad := NewQuadPrimitiveExt(mgl32.Vec3{}, mgl32.Vec2{1, 1}, shader, nil, nil)
+		quad.SetTexture(texture)
+		quad.SetTextures(map[string]*Texture{"normal_map": texture})
+		return quad
+	}
+	lit := newLitQuad()
+	lit.SetLights([]Light2D{{Radius: 10, Intensity: 1}, {Radius: 5, Intensity: 1}})
+	unlit := newLitQuad()
+	if lit.Shader() != unlit.Shader() {
+		t.Fatal("the quads don't share the program")
+	}
+	numLights := lit.Shader().GetUniform("num_lights")
+	projection := mgl32.Ident4()
+
+	lit.Draw(&projection)
+	unlit.Draw(&projection)
+
+	// The value set by the last call to num_lights
+	var last string
+	prefix := fmt.Sprintf("Uniform1iv(%d, 1, ", numLights)
+	for _, call := range r.calls {
+		if strings.HasPrefix(call, prefix) {
+			last = call
+		}
+	}
+	if expected := fmt.Sprintf("Uniform1iv(%d, 1, 0)", numLights); last != expected {
+		t.Errorf("the last num_lights set is %q, expected %q", last, expected)
+	}
+}
@@ -0,0 +1,60 @@
+package gl_utils
+
+import (
+	"sort"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// Material a shader program together with the textures and uniform values it's drawn with. Primitives sharing a
+// material are rendered the same way
+type Material struct {
+	shaderProgram *ShaderProgram
+	textures      map[string]*Texture
+	uniforms      map[string]interface{}
+}
+
+// NewMaterial creates a material using the shader program passed
+func NewMaterial(shader *ShaderProgram) *Material {
+	return &Material{
+		shaderProgram: shader,
+		textures:      make(map[string]*Texture),
+		uniforms:      make(map[string]interface{}),
+	}
+}
+
+// Shader returns the shader program of the material
+func (m *Material) Shader() *ShaderProgram {
+	return m.shaderProgram
+}
+
+// SetTexture binds a texture to the sampler uniform with the specified name
+func (m *Material) SetTexture(sampler string, texture *Texture) {
+	m.textures[sampler] = texture
+}
+
+// SetUniform sets the value of a uniform, the value is sent to the shader every time the material is applied.
+// See ShaderProgram.SetUniforms for the types accepted
+func (m *Material) SetUniform(name string, value interface{}) {
+	m.uniforms[name] = value
+}
+
+// Apply activates the shader program, binds the textures to successive texture units (in alphabetical order of the
+// sampler name, starting from unit 0) and sets the uniforms
+func (m *Material) Apply() error {
+	glc.UseProgram(m.shaderProgram.ID())
+
+	names := make([]string, 0, len(m.textures))
+	for name := range m.textures {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for i, name := range names {
+		unit := int32(i)
+		m.textures[name].BindToUnit(unit)
+		m.shaderProgram.SetUniform(name, &unit)
+	}
+	glc.ActiveTexture(gl.TEXTURE0)
+
+	return m.shaderProgram.SetUniforms(m.uniforms)
+}
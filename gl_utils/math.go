@@ -4,6 +4,7 @@ import (
 	"errors"
 	"github.com/go-gl/mathgl/mgl64"
 	"math"
+	"sort"
 
 	"github.com/go-gl/mathgl/mgl32"
 )
@@ -13,17 +14,25 @@ func CircleToPolygon(center mgl32.Vec2, radius float32, numSegments int, startAn
 	if radius <= 0 {
 		return nil, errors.New("Radius cannot be <=0")
 	}
+	return EllipseToPolygon(center, radius, radius, numSegments, startAngle)
+}
+
+// EllipseToPolygon approximate an axis aligned ellipse with a polygon. The first point is at startAngle, like in
+// CircleToPolygon
+func EllipseToPolygon(center mgl32.Vec2, radiusX, radiusY float32, numSegments int, startAngle float32) ([]mgl32.Vec2, error) {
+	if radiusX <= 0 || radiusY <= 0 {
+		return nil, errors.New("Radius cannot be <=0")
+	}
 	if numSegments < 3 {
 		return nil, errors.New("numSegments must be >= 3")
 	}
-	point := mgl32.Rotate2D(startAngle).Mul2x1(mgl32.Vec2{radius, 0})
-	vertices := make([]mgl32.Vec2, 0, numSegments*2)
-	rotation := mgl32.Rotate2D(float32((math.Pi * 2.0) / float64(numSegments)))
+	vertices := make([]mgl32.Vec2, 0, numSegments)
+	step := (math.Pi * 2.0) / float64(numSegments)
 
 	for index := 0; index < numSegments; index++ {
-		p := point.Add(center)
-		vertices = append(vertices, p)
-		point = rotation.Mul2x1(point)
+		angle := float64(startAngle) + step*float64(index)
+		p := mgl32.Vec2{radiusX * float32(math.Cos(angle)), radiusY * float32(math.Sin(angle))}
+		vertices = append(vertices, p.Add(center))
 	}
 
 	return vertices, nil
@@ -74,3 +83,49 @@ func Mat4From64to32Bits(mat mgl64.Mat4) mgl32.Mat4 {
 		float32(mat[15]),
 	}
 }
+
+// ConvexHull returns the convex hull of the points, in counter-clockwise order and without duplicated points.
+// Collinear points on the edges of the hull are discarded (Andrew's monotone chain)
+func ConvexHull(points []mgl32.Vec2) []mgl32.Vec2 {
+	sorted := make([]mgl32.Vec2, len(points))
+	copy(sorted, points)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].X() != sorted[j].X() {
+			return sorted[i].X() < sorted[j].X()
+		}
+		return sorted[i].Y() < sorted[j].Y()
+	})
+	// Remove the duplicates, which are now adjacent
+	unique := sorted[:0]
+	for _, p := range sorted {
+		if len(unique) == 0 || p != unique[len(unique)-1] {
+			unique = append(unique, p)
+		}
+	}
+	if len(unique) < 3 {
+		return unique
+	}
+
+	cross := func(o, a, b mgl32.Vec2) float32 {
+		return (a.X()-o.X())*(b.Y()-o.Y()) - (a.Y()-o.Y())*(b.X()-o.X())
+	}
+	hull := make([]mgl32.Vec2, 0, len(unique)*2)
+	// Lower hull
+	for _, p := range unique {
+		for len(hull) >= 2 && cross(hull[len(hull)-2], hull[len(hull)-1], p) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+	// Upper hull
+	lowerSize := len(hull) + 1
+	for i := len(unique) - 2; i >= 0; i-- {
+		p := unique[i]
+		for len(hull) >= lowerSize && cross(hull[len(hull)-2], hull[len(hull)-1], p) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+	// The last point is the same as the first one
+	return hull[:len(hull)-1]
+}
@@ -0,0 +1,81 @@
+package gl_utils
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// ParallaxLayer a background (or foreground) layer scrolling at a different speed than the camera
+type ParallaxLayer struct {
+	primitive *Primitive2D
+	factor    float32
+	tileX     bool
+}
+
+// NewParallaxLayer creates a layer drawing the primitive. The scroll factor is how much the layer follows the
+// world: 0 keeps it pinned to the screen, 1 moves it together with the world, values in between make it look distant
+func NewParallaxLayer(primitive *Primitive2D, factor float32) *ParallaxLayer {
+	return &ParallaxLayer{
+		primitive: primitive,
+		factor:    factor,
+	}
+}
+
+// Primitive returns the primitive drawn by the layer. Its position is the position of the layer when the camera is
+// at the origin
+func (l *ParallaxLayer) Primitive() *Primitive2D {
+	return l.primitive
+}
+
+// Factor returns the scroll factor of the layer
+func (l *ParallaxLayer) Factor() float32 {
+	return l.factor
+}
+
+// SetFactor sets the scroll factor of the layer
+func (l *ParallaxLayer) SetFactor(factor float32) {
+	l.factor = factor
+}
+
+// SetTileX repeats the primitive horizontally, side by side, to fill the width of the camera. It works with
+// primitives which are not rotated and whose texture wraps seamlessly
+func (l *ParallaxLayer) SetTileX(tile bool) {
+	l.tileX = tile
+}
+
+// Draw draws the layer as seen through the camera
+func (l *ParallaxLayer) Draw(camera *Camera2D) {
+	basePosition := l.primitive.Position()
+	offset := camera.Position().Mul(1 - l.factor)
+	position := basePosition.Add(offset.Vec3(0))
+	projection := camera.ProjectionMatrix()
+
+	if !l.tileX {
+		l.primitive.SetPosition(position)
+		l.primitive.Draw(projection)
+		l.primitive.SetPosition(basePosition)
+		return
+	}
+
+	scale := l.primitive.scale.X()
+	if l.primitive.flipX {
+		scale = -scale
+	}
+	tileWidth := float32(math.Abs(float64(l.primitive.size.X() * scale)))
+	if tileWidth == 0 {
+		return
+	}
+	left := position.X() - l.primitive.anchor.X()*scale
+	if scale < 0 {
+		left -= tileWidth
+	}
+	min, max := camera.visibleArea()
+	first := int(math.Floor(float64((min.X() - left) / tileWidth)))
+	last := int(math.Floor(float64((max.X() - left) / tileWidth)))
+	for i := first; i <= last; i++ {
+		l.primitive.SetPosition(mgl32.Vec3{position.X() + float32(i)*tileWidth, position.Y(), position.Z()})
+		l.primitive.Draw(projection)
+	}
+	l.primitive.SetPosition(basePosition)
+}
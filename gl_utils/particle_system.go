@@ -0,0 +1,237 @@
+package gl_utils
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Number of floats per particle in the instance buffer: x, y, size, r, g, b, a
+const particleInstanceSize = 7
+
+// EmitterConfig describes how new particles are emitted
+type EmitterConfig struct {
+	// Position where the particles are spawned
+	Position mgl32.Vec2
+	// Direction (in radians) of the emission
+	Direction float32
+	// Spread is the angle (in radians) of the emission cone around Direction
+	Spread float32
+	// MinSpeed and MaxSpeed are the range of the initial speed, in world units per second
+	MinSpeed float32
+	MaxSpeed float32
+	// MinLife and MaxLife are the range of the duration of a particle, in seconds
+	MinLife float32
+	MaxLife float32
+	// StartSize and EndSize are the size of a particle at birth and at death
+	StartSize float32
+	EndSize   float32
+	// StartColor and EndColor are the color of a particle at birth and at death
+	StartColor Color
+	EndColor   Color
+}
+
+type particle struct {
+	position   mgl32.Vec2
+	velocity   mgl32.Vec2
+	life       float32
+	maxLife    float32
+	size       float32
+	startSize  float32
+	endSize    float32
+	color      Color
+	startColor Color
+	endColor   Color
+}
+
+// ParticleSystem a pool of particles drawn with a single instanced draw call
+type ParticleSystem struct {
+	particles     []particle
+	numAlive      int
+	gravity       mgl32.Vec2
+	texture       *Texture
+	shaderProgram *ShaderProgram
+	vaoId         uint32
+	vboVertices   uint32
+	vboInstances  uint32
+	instanceData  []float32
+}
+
+// NewParticleSystem creates a particle system able to handle up to maxParticles alive at the same time.
+// The texture can be nil, in that case the particles are solid colored squares
+func NewParticleSystem(maxParticles int, texture *Texture) *ParticleSystem {
+	ps := &ParticleSystem{
+		particles:     make([]particle, maxParticles),
+		texture:       texture,
+		shaderProgram: SharedShaderProgram(VertexShaderParticle, "", FragmentShaderParticle),
+		instanceData:  make([]float32, maxParticles*particleInstanceSize),
+	}
+
+	glc.GenVertexArrays(1, &ps.vaoId)
+	glc.BindVertexArray(ps.vaoId)
+
+	// A unit quad centered on the origin, with its UVs
+	quad := []float32{-0.5, -0.5, 0, 0, -0.5, 0.5, 0, 1, 0.5, 0.5, 1, 1, 0.5, -0.5, 1, 0}
+	glc.GenBuffers(1, &ps.vboVertices)
+	glc.BindBuffer(gl.ARRAY_BUFFER, ps.vboVertices)
+	glc.BufferData(gl.ARRAY_BUFFER, len(quad)*Float32Size, gl.Ptr(quad), gl.STATIC_DRAW)
+	glc.EnableVertexAttribArray(0)
+	glc.VertexAttribPointer(0, 2, gl.FLOAT, false, 4*Float32Size, gl.PtrOffset(0))
+	glc.EnableVertexAttribArray(1)
+	glc.VertexAttribPointer(1, 2, gl.FLOAT, false, 4*Float32Size, gl.PtrOffset(2*Float32Size))
+
+	// Per instance data
+	stride := int32(particleInstanceSize * Float32Size)
+	glc.GenBuffers(1, &ps.vboInstances)
+	glc.BindBuffer(gl.ARRAY_BUFFER, ps.vboInstances)
+	glc.BufferData(gl.ARRAY_BUFFER, len(ps.instanceData)*Float32Size, nil, gl.DYNAMIC_DRAW)
+	glc.EnableVertexAttribArray(2)
+	glc.VertexAttribPointer(2, 3, gl.FLOAT, false, stride, gl.PtrOffset(0))
+	glc.VertexAttribDivisor(2, 1)
+	glc.EnableVertexAttribArray(3)
+	glc.VertexAttribPointer(3, 4, gl.FLOAT, false, stride, gl.PtrOffset(3*Float32Size))
+	glc.VertexAttribDivisor(3, 1)
+
+	glc.BindVertexArray(0)
+	return ps
+}
+
+// SetGravity sets the acceleration applied to all the particles, in world units per second squared
+func (ps *ParticleSystem) SetGravity(gravity mgl32.Vec2) {
+	ps.gravity = gravity
+}
+
+// NumAlive returns the number of particles currently alive
+func (ps *ParticleSystem) NumAlive() int {
+	return ps.numAlive
+}
+
+// Emit spawns n new particles. Particles exceeding the capacity of the system are discarded
+func (ps *ParticleSystem) Emit(n int, config EmitterConfig) {
+	for i := 0; i < n && ps.numAlive < len(ps.particles); i++ {
+		angle := float64(config.Direction + (rand.Float32()-0.5)*config.Spread)
+		speed := randomRange(config.MinSpeed, config.MaxSpeed)
+		life := randomRange(config.MinLife, config.MaxLife)
+		if life <= 0 {
+			continue
+		}
+		ps.particles[ps.numAlive] = particle{
+			position:   config.Position,
+			velocity:   mgl32.Vec2{float32(math.Cos(angle)) * speed, float32(math.Sin(angle)) * speed},
+			life:       life,
+			maxLife:    life,
+			size:       config.StartSize,
+			startSize:  config.StartSize,
+			endSize:    config.EndSize,
+			color:      config.StartColor,
+			startColor: config.StartColor,
+			endColor:   config.EndColor,
+		}
+		ps.numAlive++
+	}
+}
+
+// Update moves the particles and interpolates their size and color, dead particles are removed
+func (ps *ParticleSystem) Update(dt float32) {
+	for i := 0; i < ps.numAlive; {
+		p := &ps.particles[i]
+		p.life -= dt
+		if p.life <= 0 {
+			// Replace the dead particle with the last alive one
+			ps.numAlive--
+			ps.particles[i] = ps.particles[ps.numAlive]
+			continue
+		}
+		p.velocity = p.velocity.Add(ps.gravity.Mul(dt))
+		p.position = p.position.Add(p.velocity.Mul(dt))
+		t := 1 - p.life/p.maxLife
+		p.size = p.startSize + (p.endSize-p.startSize)*t
+		p.color = Color(mgl32.Vec4(p.startColor).Add(mgl32.Vec4(p.endColor).Sub(mgl32.Vec4(p.startColor)).Mul(t)))
+		i++
+	}
+}
+
+// Draw draws all the alive particles
+func (ps *ParticleSystem) Draw(projectionMatrix *mgl32.Mat4) {
+	if ps.numAlive == 0 {
+		return
+	}
+	data := ps.instanceData[:ps.numAlive*particleInstanceSize]
+	for i := 0; i < ps.numAlive; i++ {
+		p := &ps.particles[i]
+		offset := i * particleInstanceSize
+		data[offset] = p.position.X()
+		data[offset+1] = p.position.Y()
+		data[offset+2] = p.size
+		copy(data[offset+3:offset+7], p.color[:])
+	}
+
+	glc.UseProgram(ps.shaderProgram.ID())
+	ps.shaderProgram.SetUniform("projection", projectionMatrix)
+	var textured int32
+	if ps.texture != nil {
+		textured = 1
+		ps.texture.BindToUnit(0)
+	}
+	ps.shaderProgram.SetUniform("textured", &textured)
+
+	glc.BindBuffer(gl.ARRAY_BUFFER, ps.vboInstances)
+	glc.BufferSubData(gl.ARRAY_BUFFER, 0, len(data)*Float32Size, gl.Ptr(data))
+	countBufferUpload()
+	glc.BindBuffer(gl.ARRAY_BUFFER, 0)
+
+	glc.BindVertexArray(ps.vaoId)
+	glc.DrawArraysInstanced(gl.TRIANGLE_FAN, 0, 4, int32(ps.numAlive))
+	countDrawCall(4 * ps.numAlive)
+	glc.BindVertexArray(0)
+}
+
+func randomRange(min, max float32) float32 {
+	return min + rand.Float32()*(max-min)
+}
+
+const (
+	// VertexShaderParticle places and scales a quad for each particle instance
+	VertexShaderParticle = `
+        #version 410 core
+
+        uniform mat4 projection;
+
+        layout(location=0) in vec2 vertex;
+        layout(location=1) in vec2 uv;
+        layout(location=2) in vec3 instance_position_size;
+        layout(location=3) in vec4 instance_color;
+
+        out vec2 uv_out;
+        out vec4 color_out;
+
+        void main() {
+            vec2 position = instance_position_size.xy + vertex * instance_position_size.z;
+            gl_Position = projection * vec4(position, 0, 1);
+            uv_out = uv;
+            color_out = instance_color;
+        }
+        ` + "\x00"
+
+	// FragmentShaderParticle tints the particle texture with the particle color
+	FragmentShaderParticle = `
+        #version 410 core
+
+        in vec2 uv_out;
+        in vec4 color_out;
+        out vec4 out_color;
+
+        uniform sampler2D tex;
+        uniform int textured;
+
+        void main() {
+            if (textured == 1) {
+                out_color = texture(tex, uv_out) * color_out;
+            } else {
+                out_color = color_out;
+            }
+        }
+        ` + "\x00"
+)
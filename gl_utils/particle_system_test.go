@@ -0,0 +1,32 @@
+package gl_utils
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestParticleSystemDrawsAliveParticles(t *testing.T) {
+	r := useRecordingGL(t)
+	ps := NewParticleSystem(10, nil)
+	projection := mgl32.Ident4()
+	r.reset()
+
+	ps.Draw(&projection)
+	if calls := drawCalls(r); len(calls) != 0 {
+		t.Errorf("draw calls %v without particles", calls)
+	}
+
+	ps.Emit(15, EmitterConfig{MinLife: 1, MaxLife: 1, StartSize: 1, EndSize: 1, StartColor: Color{1, 1, 1, 1}})
+	if ps.NumAlive() != 10 {
+		t.Fatalf("%d particles alive, expected the maximum of 10", ps.NumAlive())
+	}
+	ps.Draw(&projection)
+
+	expected := fmt.Sprintf("DrawArraysInstanced(%d, 0, 4, 10)", gl.TRIANGLE_FAN)
+	if indexOf(r, expected) < 0 {
+		t.Errorf("%s hasn't been called: %v", expected, r.calls)
+	}
+}
@@ -0,0 +1,157 @@
+package gl_utils
+
+import (
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// PlotPrimitive a line chart of a series of values, spread evenly across the width of the plot. The origin of the
+// plot is its top-left corner and higher values are drawn higher, the lowest value of the range lies on the bottom
+// edge
+type PlotPrimitive struct {
+	Primitive2D
+	values    []float32
+	bounds    mgl32.Vec2
+	autoScale bool
+	minValue  float32
+	maxValue  float32
+	filled    bool
+}
+
+// NewPlotPrimitive creates a plot of the values, as big as bounds (width and height). The values are scaled
+// automatically to fill the height of the plot, see SetRange for a fixed scale
+func NewPlotPrimitive(values []float32, bounds mgl32.Vec2) *PlotPrimitive {
+	shader := SharedShaderProgram(VertexShaderBase, "", FragmentShaderSolidColor)
+	p := &PlotPrimitive{
+		Primitive2D: *newPrimitive2D(mgl32.Vec3{}, mgl32.Vec2{1, 1}, shader),
+		bounds:      bounds,
+		autoScale:   true,
+	}
+	p.arrayMode = gl.LINE_STRIP
+	p.UpdateValues(values)
+	return p
+}
+
+// Values returns the values plotted
+func (p *PlotPrimitive) Values() []float32 {
+	return p.values
+}
+
+// UpdateValues replaces the values plotted. As long as the number of values doesn't grow, only the content of the
+// vertex buffer is updated, so it can be called every frame for live graphs
+func (p *PlotPrimitive) UpdateValues(values []float32) {
+	p.values = values
+	p.rebuildGeometry()
+}
+
+// PlotBounds returns the width and the height of the plot
+func (p *PlotPrimitive) PlotBounds() mgl32.Vec2 {
+	return p.bounds
+}
+
+// SetPlotBounds sets the width and the height of the plot
+func (p *PlotPrimitive) SetPlotBounds(bounds mgl32.Vec2) {
+	p.bounds = bounds
+	p.rebuildGeometry()
+}
+
+// Range returns the values lying on the bottom and on the top edge of the plot
+func (p *PlotPrimitive) Range() (float32, float32) {
+	if p.autoScale {
+		return valuesRange(p.values)
+	}
+	return p.minValue, p.maxValue
+}
+
+// SetRange fixes the values lying on the bottom and on the top edge of the plot, disabling the automatic scaling.
+// Values outside the range are drawn outside the plot
+func (p *PlotPrimitive) SetRange(min float32, max float32) {
+	p.autoScale = false
+	p.minValue = min
+	p.maxValue = max
+	p.rebuildGeometry()
+}
+
+// SetAutoScale scales the plot to fit the range of its values (the default)
+func (p *PlotPrimitive) SetAutoScale() {
+	p.autoScale = true
+	p.rebuildGeometry()
+}
+
+// Filled returns whether the area under the curve is filled
+func (p *PlotPrimitive) Filled() bool {
+	return p.filled
+}
+
+// SetFilled fills the area between the curve and the bottom edge of the plot, instead of drawing just the line
+func (p *PlotPrimitive) SetFilled(filled bool) {
+	p.filled = filled
+	if filled {
+		p.arrayMode = gl.TRIANGLE_STRIP
+	} else {
+		p.arrayMode = gl.LINE_STRIP
+	}
+	p.rebuildGeometry()
+}
+
+func (p *PlotPrimitive) rebuildGeometry() {
+	if len(p.values) == 0 {
+		// Nothing to upload, an empty buffer can't be passed to OpenGL
+		p.arraySize = 0
+		return
+	}
+	min, max := p.Range()
+	valueScale := float32(0)
+	if max != min {
+		valueScale = p.bounds.Y() / (max - min)
+	}
+	step := float32(0)
+	if len(p.values) > 1 {
+		step = p.bounds.X() / float32(len(p.values)-1)
+	}
+
+	verticesPerValue := 2
+	if p.filled {
+		verticesPerValue = 4
+	}
+	vertices := make([]float32, 0, len(p.values)*verticesPerValue)
+	for i, value := range p.values {
+		x := step * float32(i)
+		y := p.bounds.Y() - (value-min)*valueScale
+		vertices = append(vertices, x, y)
+		if p.filled {
+			vertices = append(vertices, x, p.bounds.Y())
+		}
+	}
+	p.uploadPlotVertices(vertices)
+}
+
+// uploadPlotVertices updates the vertex buffer in place when it's big enough, otherwise it allocates a new one
+func (p *PlotPrimitive) uploadPlotVertices(vertices []float32) {
+	if p.vboVertices == 0 || len(vertices) > p.verticesCapacity {
+		p.SetVertices(vertices)
+		return
+	}
+	p.vertices = vertices
+	glc.BindBuffer(gl.ARRAY_BUFFER, p.vboVertices)
+	glc.BufferSubData(gl.ARRAY_BUFFER, 0, len(vertices)*Float32Size, gl.Ptr(vertices))
+	countBufferUpload()
+	p.arraySize = int32(len(vertices) / 2)
+}
+
+// valuesRange returns the lowest and the highest of the values
+func valuesRange(values []float32) (float32, float32) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+	min, max := values[0], values[0]
+	for _, value := range values[1:] {
+		if value < min {
+			min = value
+		}
+		if value > max {
+			max = value
+		}
+	}
+	return min, max
+}
@@ -1,15 +1,55 @@
 package gl_utils
 
-import "github.com/go-gl/mathgl/mgl32"
+import (
+	"sort"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
 
 type Primitive struct {
 	vaoId         uint32
 	vboVertices   uint32
 	vboUVCoords   uint32
+	vboColors     uint32
+	vboUVCoords2  uint32
+	vboLayers     uint32
+	vboData       uint32
+	vertices      []float32
 	arrayMode     uint32
 	arraySize     int32
 	texture       *Texture
+	textures      map[string]*Texture
 	shaderProgram *ShaderProgram
+	material      *Material
+	// Shader of the primitive, replaced by the one of the material while a material is set
+	baseShader *ShaderProgram
+
+	// Number of floats the vertices buffer can hold
+	verticesCapacity int
+}
+
+// bindVertexArray binds the VAO of the primitive, creating it the first time
+func (p *Primitive) bindVertexArray() {
+	if p.vaoId == 0 {
+		glc.GenVertexArrays(1, &p.vaoId)
+	}
+	glc.BindVertexArray(p.vaoId)
+}
+
+// Release deletes the vertex array and the buffers of the primitive. Shader and textures are not released since
+// they can be shared with other primitives
+func (p *Primitive) Release() {
+	for _, vbo := range []*uint32{&p.vboVertices, &p.vboUVCoords, &p.vboColors, &p.vboUVCoords2, &p.vboLayers, &p.vboData} {
+		if *vbo != 0 {
+			glc.DeleteBuffers(1, vbo)
+			*vbo = 0
+		}
+	}
+	if p.vaoId != 0 {
+		glc.DeleteVertexArrays(1, &p.vaoId)
+		p.vaoId = 0
+	}
 }
 
 func (p *Primitive) SetTexture(texture *Texture) {
@@ -20,6 +60,61 @@ func (p *Primitive) Texture() *Texture {
 	return p.texture
 }
 
+// SetTextures sets additional textures, each one bound to the sampler uniform with the same name. They are bound
+// to successive texture units in alphabetical order of the sampler name, after the unit 0 used by SetTexture
+func (p *Primitive) SetTextures(textures map[string]*Texture) {
+	p.textures = textures
+}
+
+// Textures returns the additional textures bound to named samplers
+func (p *Primitive) Textures() map[string]*Texture {
+	return p.textures
+}
+
+// bindTextures binds the main texture to unit 0 and the named textures to the following units. The shader program
+// must be in use
+func (p *Primitive) bindTextures() {
+	if p.texture != nil {
+		p.texture.BindToUnit(0)
+	}
+	if len(p.textures) == 0 {
+		return
+	}
+	names := make([]string, 0, len(p.textures))
+	for name := range p.textures {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	unit := int32(1)
+	for _, name := range names {
+		p.textures[name].BindToUnit(unit)
+		p.shaderProgram.SetUniform(name, &unit)
+		unit++
+	}
+	glc.ActiveTexture(gl.TEXTURE0)
+}
+
+// SetMaterial sets the material used to draw the primitive, replacing its shader. While a material is set the
+// textures of the primitive are ignored in favour of the ones of the material. Pass nil to remove the material and
+// go back to the shader of the primitive
+func (p *Primitive) SetMaterial(material *Material) {
+	if material != nil {
+		if p.material == nil {
+			p.baseShader = p.shaderProgram
+		}
+		p.shaderProgram = material.shaderProgram
+	} else if p.material != nil {
+		p.shaderProgram = p.baseShader
+		p.baseShader = nil
+	}
+	p.material = material
+}
+
+// Material returns the material used to draw the primitive, nil if there is none
+func (p *Primitive) Material() *Material {
+	return p.material
+}
+
 func (p *Primitive) SetShader(shader *ShaderProgram) {
 	p.shaderProgram = shader
 }
@@ -28,5 +123,26 @@ func (p *Primitive) Shader() *ShaderProgram {
 	return p.shaderProgram
 }
 
+// SetArrayMode sets the kind of primitives rendered by gl.DrawArrays. Valid values are gl.POINTS, gl.LINES,
+// gl.LINE_STRIP, gl.LINE_LOOP, gl.TRIANGLES, gl.TRIANGLE_STRIP, gl.TRIANGLE_FAN and their adjacency variants
+func (p *Primitive) SetArrayMode(mode uint32) {
+	p.arrayMode = mode
+}
+
+// ArrayMode returns the kind of primitives rendered by gl.DrawArrays
+func (p *Primitive) ArrayMode() uint32 {
+	return p.arrayMode
+}
+
+// SetArraySize sets the number of vertices rendered by gl.DrawArrays. Uploading new vertices resets it
+func (p *Primitive) SetArraySize(count int32) {
+	p.arraySize = count
+}
+
+// ArraySize returns the number of vertices rendered by gl.DrawArrays
+func (p *Primitive) ArraySize() int32 {
+	return p.arraySize
+}
+
 func (p *Primitive) Draw(projectionMatrix *mgl32.Mat4) {
 }
@@ -1,7 +1,10 @@
 package gl_utils
 
 import (
+	"errors"
 	"fmt"
+	"math"
+
 	"github.com/go-gl/gl/v4.1-core/gl"
 	"github.com/go-gl/mathgl/mgl32"
 )
@@ -19,21 +22,50 @@ type ModelMatrix struct {
 	rotation    mgl32.Mat4
 	scale       mgl32.Mat4
 	anchor      mgl32.Mat4
+	inverse     mgl32.Mat4
 	dirty       bool
 }
 
 // Primitive2D a drawing primitive on the XY plane
 type Primitive2D struct {
 	Primitive
-	position    mgl32.Vec3
-	scale       mgl32.Vec2
-	size        mgl32.Vec2
-	anchor      mgl32.Vec2
-	angle       float32
-	flipX       bool
-	flipY       bool
-	color       Color
-	modelMatrix ModelMatrix
+	position mgl32.Vec3
+	scale    mgl32.Vec2
+	size     mgl32.Vec2
+	anchor   mgl32.Vec2
+	// normalizedAnchor is the anchor as a fraction of the size, valid only when anchorNormalized is set
+	normalizedAnchor mgl32.Vec2
+	anchorNormalized bool
+	angle            float32
+	flipX            bool
+	flipY            bool
+	flipUVX          bool
+	flipUVY          bool
+	uvCoords         []float32
+	color            Color
+	colorRef         *Color
+	modelMatrix      ModelMatrix
+	pointSize        float32
+	pointSizeMode    PointSizeMode
+	lights           []Light2D
+	ambientLight     Color
+	cullMode         CullMode
+	frontFaceCW      bool
+	transparent      bool
+	rotationPivot    mgl32.Vec2
+	hasRotationPivot bool
+	depth            depthSettings
+	pixelSnap        bool
+	outline          *primitiveOutline
+	shadow           *primitiveShadow
+	blendMode        BlendMode
+	polygon          []mgl32.Vec2
+	uvTransform      *mgl32.Mat3
+	tiling           *textureTiling
+	// Transformation of the group the primitive is being drawn with, if any
+	parentMatrix *mgl32.Mat4
+	// Model matrix used instead of the primitive one by DrawWithModel
+	modelOverride *mgl32.Mat4
 }
 
 // SetPosition sets the X,Y,Z position of the primitive. Z is used for the drawing order
@@ -48,16 +80,34 @@ func (p *Primitive2D) Position() mgl32.Vec3 {
 	return p.position
 }
 
+// Anchor returns the anchor point of the primitive, in pixels
+func (p *Primitive2D) Anchor() mgl32.Vec2 {
+	return p.anchor
+}
+
 // SetAnchor sets the anchor point of the primitive, this will be the point placed at Position
 func (p *Primitive2D) SetAnchor(anchor mgl32.Vec2) {
+	p.anchorNormalized = false
+	p.setAnchor(anchor)
+}
+
+// SetAnchorNormalized sets the anchor as a fraction of the size of the primitive (0,0 is the top-left corner,
+// 0.5,0.5 the center and 1,1 the bottom-right corner). The anchor follows the changes of size
+func (p *Primitive2D) SetAnchorNormalized(nx, ny float32) {
+	p.normalizedAnchor = mgl32.Vec2{nx, ny}
+	p.anchorNormalized = true
+	p.setAnchor(mgl32.Vec2{nx * p.size.X(), ny * p.size.Y()})
+}
+
+func (p *Primitive2D) setAnchor(anchor mgl32.Vec2) {
 	p.anchor = anchor
 	p.modelMatrix.anchor = mgl32.Translate3D(-p.anchor.X(), -p.anchor.Y(), 0)
 	p.modelMatrix.dirty = true
 }
 
-// SetAnchorToCenter sets the anchor at the center of the primitive
+// SetAnchorToCenter sets the anchor at the center of the primitive. The anchor stays centered when the size changes
 func (p *Primitive2D) SetAnchorToCenter() {
-	p.SetAnchor(mgl32.Vec2{p.size[0] / 2.0, p.size[1] / 2.0})
+	p.SetAnchorNormalized(0.5, 0.5)
 }
 
 // Angle in radians
@@ -72,6 +122,47 @@ func (p *Primitive2D) SetAngle(radians float32) {
 	p.modelMatrix.dirty = true
 }
 
+// AngleDegrees returns the rotation of the primitive in degrees
+func (p *Primitive2D) AngleDegrees() float32 {
+	return mgl32.RadToDeg(p.angle)
+}
+
+// SetAngleDegrees sets the rotation of the primitive in degrees
+func (p *Primitive2D) SetAngleDegrees(degrees float32) {
+	p.SetAngle(mgl32.DegToRad(degrees))
+}
+
+// SetPixelSnap rounds the translation of the primitive to whole world units, so that pixel art drawn at fractional
+// positions stays crisp. It's meant for primitives rotated by multiples of 90 degrees: with other angles the corners
+// don't fall on whole units anyway. With a zoom of 1 a world unit is a screen pixel, use it together with the
+// pixel snapping of Camera2D to have the camera move by whole pixels as well
+func (p *Primitive2D) SetPixelSnap(snap bool) {
+	p.pixelSnap = snap
+	p.modelMatrix.dirty = true
+}
+
+// SetRotationPivot sets the point the primitive rotates around, in the same units of the anchor. The anchor is
+// still the point placed at Position when the primitive isn't rotated, the rotation then moves it around the pivot
+func (p *Primitive2D) SetRotationPivot(pivot mgl32.Vec2) {
+	p.rotationPivot = pivot
+	p.hasRotationPivot = true
+	p.modelMatrix.dirty = true
+}
+
+// RotationPivot returns the point the primitive rotates around, which is the anchor unless a pivot has been set
+func (p *Primitive2D) RotationPivot() mgl32.Vec2 {
+	if p.hasRotationPivot {
+		return p.rotationPivot
+	}
+	return p.anchor
+}
+
+// ClearRotationPivot makes the primitive rotate around its anchor again
+func (p *Primitive2D) ClearRotationPivot() {
+	p.hasRotationPivot = false
+	p.modelMatrix.dirty = true
+}
+
 // Size in pixels
 func (p *Primitive2D) Size() mgl32.Vec2 {
 	return mgl32.Vec2{p.size.X(), p.size.Y()}
@@ -82,6 +173,10 @@ func (p *Primitive2D) SetSize(size mgl32.Vec2) {
 	p.size = size
 	p.modelMatrix.size = mgl32.Scale3D(p.size.X(), p.size.Y(), 1)
 	p.modelMatrix.dirty = true
+	if p.anchorNormalized {
+		p.setAnchor(mgl32.Vec2{p.normalizedAnchor.X() * p.size.X(), p.normalizedAnchor.Y() * p.size.Y()})
+	}
+	p.updateTilingFromSize()
 }
 
 // SetSizeFromTexture sets the size of the current primitive to the pixel size of the texture
@@ -92,12 +187,27 @@ func (p *Primitive2D) SetSizeFromTexture() {
 	p.SetSize(mgl32.Vec2{float32(p.texture.width), float32(p.texture.height)})
 }
 
+// Scale returns the scaling factor on X and Y of the primitive
+func (p *Primitive2D) Scale() mgl32.Vec2 {
+	return p.scale
+}
+
 // SetScale sets the scaling factor on X and Y for the primitive. The scaling respects the anchor and the rotation
 func (p *Primitive2D) SetScale(scale mgl32.Vec2) {
 	p.scale = scale
 	p.rebuildScaleMatrix()
 }
 
+// FlipX returns whether the primitive is flipped around the Y axis
+func (p *Primitive2D) FlipX() bool {
+	return p.flipX
+}
+
+// FlipY returns whether the primitive is flipped around the X axis
+func (p *Primitive2D) FlipY() bool {
+	return p.flipY
+}
+
 // SetFlipX flips the primitive around the Y axis
 func (p *Primitive2D) SetFlipX(flipX bool) {
 	p.flipX = flipX
@@ -112,31 +222,228 @@ func (p *Primitive2D) SetFlipY(flipY bool) {
 
 // Color return the color passed to the shader
 func (p *Primitive2D) Color() Color {
-	return p.color
+	return *p.drawColor()
 }
 
-// SetColor sets the color passed to the shader
+// SetColor sets the color passed to the shader. The color is copied, and it replaces the one set by SetColorRef
 func (p *Primitive2D) SetColor(color Color) {
 	p.color = color
+	p.colorRef = nil
+}
+
+// SetColorRef makes the primitive use the color pointed by color, which is read every time the primitive is drawn.
+// The color is shared, not copied: changing it re-tints all the primitives referencing it. For the same reason the
+// color must not be changed through one of them expecting the others to keep the old one. Pass nil, or call SetColor,
+// to go back to the color of the primitive
+func (p *Primitive2D) SetColorRef(color *Color) {
+	p.colorRef = color
+}
+
+// ColorRef returns the shared color set with SetColorRef, nil if the primitive uses its own color
+func (p *Primitive2D) ColorRef() *Color {
+	return p.colorRef
+}
+
+// drawColor returns the color the primitive is drawn with
+func (p *Primitive2D) drawColor() *Color {
+	if p.colorRef != nil {
+		return p.colorRef
+	}
+	return &p.color
+}
+
+// Transparent returns whether the primitive has been flagged as transparent
+func (p *Primitive2D) Transparent() bool {
+	return p.transparent
+}
+
+// SetTransparent flags the primitive as transparent, for primitives whose color is opaque but whose texture isn't.
+// Transparent primitives are drawn after the opaque ones by DrawList
+func (p *Primitive2D) SetTransparent(transparent bool) {
+	p.transparent = transparent
+}
+
+// PointSize returns the size of the points of a points primitive, in pixels or world units (see SetPointSizeMode)
+func (p *Primitive2D) PointSize() float32 {
+	return p.pointSize
+}
+
+// SetPointSize sets the size of the points of a points primitive, in pixels or world units (see SetPointSizeMode)
+func (p *Primitive2D) SetPointSize(size float32) {
+	p.pointSize = size
 }
 
 // SetUniforms sets the shader's uniform variables
 func (p *Primitive2D) SetUniforms() {
-	p.shaderProgram.SetUniform("color", &p.color)
-	p.shaderProgram.SetUniform("model", p.ModelMatrix())
+	p.shaderProgram.SetUniform("color", p.drawColor())
+	model := p.drawModelMatrix()
+	p.shaderProgram.SetUniform("model", &model)
+	if p.arrayMode == gl.POINTS {
+		p.shaderProgram.SetUniform("point_size", &p.pointSize)
+	}
+	if p.shaderProgram.GetUniform("uv_transform") >= 0 {
+		uvTransform := mgl32.Ident3()
+		if p.uvTransform != nil {
+			uvTransform = *p.uvTransform
+		}
+		p.shaderProgram.SetUniform("uv_transform", &uvTransform)
+	}
+	if timeUniform := p.shaderProgram.GetUniform("time"); timeUniform >= 0 {
+		glc.Uniform1f(timeUniform, shaderTime)
+	}
+	if p.shaderProgram.GetUniform("num_lights") >= 0 {
+		// Set even without lights, the program can be shared with a lit primitive
+		p.setLightUniforms()
+	}
+}
+
+// drawModelMatrix returns the model matrix to draw the primitive with, taking into account the group it's drawn
+// with and the matrix passed to DrawWithModel
+func (p *Primitive2D) drawModelMatrix() mgl32.Mat4 {
+	model := *p.ModelMatrix()
+	if p.modelOverride != nil {
+		model = *p.modelOverride
+	}
+	if p.parentMatrix != nil {
+		model = p.parentMatrix.Mul4(model)
+	}
+	return model
 }
 
 // Draw draws the primitive
 func (p *Primitive2D) Draw(projectionMatrix *mgl32.Mat4) {
-	shaderID := p.shaderProgram.ID()
-	if p.texture != nil {
-		p.texture.Bind()
+	p.draw(projectionMatrix, nil, 0, p.arraySize)
+}
+
+// DrawWithModel draws the primitive with the model matrix passed instead of its own, the transformation of the
+// primitive is left untouched
+func (p *Primitive2D) DrawWithModel(projectionMatrix *mgl32.Mat4, modelMatrix *mgl32.Mat4) {
+	p.modelOverride = modelMatrix
+	p.Draw(projectionMatrix)
+	p.modelOverride = nil
+}
+
+// DrawWithView draws the primitive keeping the projection and the view transformations separated. The shader
+// receives them as the 'projection' and 'view' uniforms (see VertexShaderView)
+func (p *Primitive2D) DrawWithView(projectionMatrix *mgl32.Mat4, viewMatrix *mgl32.Mat4) {
+	p.draw(projectionMatrix, viewMatrix, 0, p.arraySize)
+}
+
+// IsReady returns whether the primitive has everything it needs to be drawn (see Ready for the details)
+func (p *Primitive2D) IsReady() bool {
+	return p.Ready() == nil
+}
+
+// Ready returns an error describing what is missing for the primitive to be drawn: the vertex array and its
+// vertices, a shader program and, if the shader samples the 'tex' uniform, a texture
+func (p *Primitive2D) Ready() error {
+	if p.vaoId == 0 {
+		return errors.New("the vertex array hasn't been created")
+	}
+	if p.vboVertices == 0 && p.vboData == 0 {
+		return errors.New("no vertices have been uploaded")
+	}
+	if p.shaderProgram == nil {
+		return errors.New("no shader program is set")
+	}
+	if p.material == nil && p.texture == nil && p.shaderProgram.GetUniform("tex") >= 0 {
+		return errors.New("the shader samples a texture but no texture is set")
+	}
+	return nil
+}
+
+// DrawTo draws the primitive into a render target, restoring the previous framebuffer and viewport afterwards
+func (p *Primitive2D) DrawTo(target *RenderTarget, projectionMatrix *mgl32.Mat4) {
+	target.Bind()
+	p.Draw(projectionMatrix)
+	target.Unbind()
+}
+
+// DrawAt draws the primitive once for each transformation, binding shader, textures and vertex array only once.
+// Each transformation is applied on top of the transformation of the primitive (including the one of its group).
+// Shadow and outline are drawn for each instance
+func (p *Primitive2D) DrawAt(projectionMatrix *mgl32.Mat4, transforms []mgl32.Mat4) {
+	if len(transforms) == 0 {
+		return
+	}
+	p.draw(projectionMatrix, nil, 0, p.arraySize, transforms...)
+}
+
+// DrawRange draws only count vertices of the primitive, starting from the vertex first. Useful to reveal a shape
+// progressively without uploading its vertices again
+func (p *Primitive2D) DrawRange(projectionMatrix *mgl32.Mat4, first int32, count int32) {
+	if first < 0 || count < 0 || first+count > p.arraySize {
+		fmt.Printf("Error: the range %d-%d is outside of the %d vertices of the primitive\n", first, first+count, p.arraySize)
+		return
+	}
+	p.draw(projectionMatrix, nil, first, count)
+}
+
+// draw draws count vertices of the primitive starting from first, once for each of the transforms if any is passed
+func (p *Primitive2D) draw(projectionMatrix *mgl32.Mat4, viewMatrix *mgl32.Mat4, first int32, count int32, transforms ...mgl32.Mat4) {
+	if count == 0 {
+		// Nothing to draw, e.g. an empty text
+		return
+	}
+	if err := p.Ready(); err != nil {
+		fmt.Printf("Error: cannot draw the primitive: %s\n", err)
+		return
+	}
+	model := p.drawModelMatrix()
+	if len(transforms) == 0 {
+		p.drawShadow(projectionMatrix, viewMatrix, model)
+		p.drawOutline(projectionMatrix, viewMatrix, model)
+	} else {
+		// Each instance gets its own shadow and outline, all of them below the instances
+		for _, transform := range transforms {
+			p.drawShadow(projectionMatrix, viewMatrix, transform.Mul4(model))
+		}
+		for _, transform := range transforms {
+			p.drawOutline(projectionMatrix, viewMatrix, transform.Mul4(model))
+		}
+	}
+	if p.material != nil {
+		if err := p.material.Apply(); err != nil && debugDraw {
+			fmt.Println(err)
+		}
+	} else {
+		glc.UseProgram(p.shaderProgram.ID())
+		p.bindTextures()
 	}
-	gl.UseProgram(shaderID)
 	p.shaderProgram.SetUniform("projection", projectionMatrix)
+	if viewMatrix != nil {
+		p.shaderProgram.SetUniform("view", viewMatrix)
+	}
 	p.SetUniforms()
-	gl.BindVertexArray(p.vaoId)
-	gl.DrawArrays(p.arrayMode, 0, p.arraySize)
+	if p.arrayMode == gl.POINTS {
+		glc.Enable(gl.PROGRAM_POINT_SIZE)
+		if p.pointSizeMode == PointSizeWorld {
+			p.setWorldPointSize(projectionMatrix, viewMatrix)
+		}
+	}
+	cullingState := p.applyFaceCulling()
+	depthState := p.applyDepthSettings()
+	blendState := p.applyBlendMode()
+	glc.BindVertexArray(p.vaoId)
+	if len(transforms) == 0 {
+		glc.DrawArrays(p.arrayMode, first, count)
+		countDrawCall(int(count))
+	} else {
+		for _, transform := range transforms {
+			instance := transform.Mul4(model)
+			p.shaderProgram.SetUniform("model", &instance)
+			glc.DrawArrays(p.arrayMode, first, count)
+			countDrawCall(int(count))
+		}
+	}
+	restoreBlendState(blendState)
+	restoreDepthState(depthState)
+	restoreFaceCulling(cullingState)
+	if debugDraw {
+		if err := CheckGLError("Primitive2D.Draw"); err != nil {
+			fmt.Println(err)
+		}
+	}
 }
 
 func (p *Primitive2D) rebuildMatrices() {
@@ -164,7 +471,18 @@ func (p *Primitive2D) rebuildScaleMatrix() {
 
 func (p *Primitive2D) rebuildModelMatrix() {
 	if p.modelMatrix.dirty {
-		p.modelMatrix.Mat4 = p.modelMatrix.translation.Mul4(p.modelMatrix.rotation).Mul4(p.modelMatrix.scale).Mul4(p.modelMatrix.anchor).Mul4(p.modelMatrix.size)
+		rotation := p.modelMatrix.rotation
+		if p.hasRotationPivot {
+			// Rotate around the pivot, expressed relative to the anchor and scaled like the geometry
+			offset := p.modelMatrix.scale.Mul4x1(p.rotationPivot.Sub(p.anchor).Vec4(0, 1))
+			rotation = mgl32.Translate3D(offset.X(), offset.Y(), 0).Mul4(rotation).Mul4(mgl32.Translate3D(-offset.X(), -offset.Y(), 0))
+		}
+		p.modelMatrix.Mat4 = p.modelMatrix.translation.Mul4(rotation).Mul4(p.modelMatrix.scale).Mul4(p.modelMatrix.anchor).Mul4(p.modelMatrix.size)
+		if p.pixelSnap {
+			p.modelMatrix.Mat4[12] = float32(math.Round(float64(p.modelMatrix.Mat4[12])))
+			p.modelMatrix.Mat4[13] = float32(math.Round(float64(p.modelMatrix.Mat4[13])))
+		}
+		p.modelMatrix.inverse = p.modelMatrix.Mat4.Inv()
 		p.modelMatrix.dirty = false
 	}
 }
@@ -175,21 +493,150 @@ func (p *Primitive2D) ModelMatrix() *mgl32.Mat4 {
 	return &p.modelMatrix.Mat4
 }
 
+// TranslationMatrix returns the translation to the position, the last transformation of the model matrix
+func (p *Primitive2D) TranslationMatrix() mgl32.Mat4 {
+	return p.modelMatrix.translation
+}
+
+// RotationMatrix returns the rotation around the Z axis, without the rotation pivot
+func (p *Primitive2D) RotationMatrix() mgl32.Mat4 {
+	return p.modelMatrix.rotation
+}
+
+// ScaleMatrix returns the scaling, flips included
+func (p *Primitive2D) ScaleMatrix() mgl32.Mat4 {
+	return p.modelMatrix.scale
+}
+
+// AnchorMatrix returns the translation moving the anchor to the origin
+func (p *Primitive2D) AnchorMatrix() mgl32.Mat4 {
+	return p.modelMatrix.anchor
+}
+
+// SizeMatrix returns the scaling of the vertices to the size of the primitive, the first transformation of the
+// model matrix
+func (p *Primitive2D) SizeMatrix() mgl32.Mat4 {
+	return p.modelMatrix.size
+}
+
+// Decompose returns the translation, the rotation (in radians) and the scale of the primitive, flips included as
+// negative scale factors
+func (p *Primitive2D) Decompose() (translation mgl32.Vec2, rotation float32, scale mgl32.Vec2) {
+	scale = p.scale
+	if p.flipX {
+		scale[0] = -scale[0]
+	}
+	if p.flipY {
+		scale[1] = -scale[1]
+	}
+	return p.position.Vec2(), p.angle, scale
+}
+
+// Bounds returns the min and max corners of the axis aligned box containing the primitive, in world coordinates.
+// The primitive transformation is taken into account. For geometry uploaded with SetVertexData the box of the
+// primitive size is used
+func (p *Primitive2D) Bounds() (mgl32.Vec2, mgl32.Vec2) {
+	p.rebuildModelMatrix()
+	vertices := p.vertices
+	if len(vertices) == 0 {
+		vertices = []float32{0, 0, 1, 0, 1, 1, 0, 1}
+	}
+	points := make([]mgl32.Vec2, 0, len(vertices)/2)
+	for i := 0; i+1 < len(vertices); i += 2 {
+		point := mgl32.TransformCoordinate(mgl32.Vec3{vertices[i], vertices[i+1], 0}, p.modelMatrix.Mat4)
+		points = append(points, point.Vec2())
+	}
+	return GetBoundingBox(points)
+}
+
+// Points returns the vertices of the polygon the primitive was generated from, in its local space, e.g. to use the
+// same shape for collisions. Only the primitives created by NewRegularPolygonPrimitive, NewStarPrimitive and
+// NewConvexHullPrimitive have them, for the others it returns nil. The slice is a copy
+func (p *Primitive2D) Points() []mgl32.Vec2 {
+	if p.polygon == nil {
+		return nil
+	}
+	points := make([]mgl32.Vec2, len(p.polygon))
+	copy(points, p.polygon)
+	return points
+}
+
+// LocalToWorld transforms a point from the local space of the primitive (the space of its vertices) to world space
+func (p *Primitive2D) LocalToWorld(point mgl32.Vec2) mgl32.Vec2 {
+	p.rebuildModelMatrix()
+	return mgl32.TransformCoordinate(point.Vec3(0), p.modelMatrix.Mat4).Vec2()
+}
+
+// WorldToLocal transforms a point from world space to the local space of the primitive. A primitive scaled to zero
+// has no inverse transformation, in that case the result is the origin
+func (p *Primitive2D) WorldToLocal(point mgl32.Vec2) mgl32.Vec2 {
+	p.rebuildModelMatrix()
+	return mgl32.TransformCoordinate(point.Vec3(0), p.modelMatrix.inverse).Vec2()
+}
+
+// BakeTransform applies the current transformation to the vertices, uploads them again and resets the
+// transformation, so that the model matrix becomes a translation along Z only (the depth is kept). Use it for static
+// geometry. The transformation setters called afterwards compose on top of the baked geometry, e.g. SetAngle rotates
+// it around the world origin. A flip baked into the vertices reverses their winding, which matters for face culling.
+// It fails for geometry uploaded with SetVertexData, since no copy of the vertices is kept
+func (p *Primitive2D) BakeTransform() error {
+	if len(p.vertices) == 0 {
+		return errors.New("the primitive has no vertices to transform")
+	}
+	p.rebuildModelMatrix()
+	vertices := make([]float32, len(p.vertices))
+	for i := 0; i+1 < len(p.vertices); i += 2 {
+		point := mgl32.TransformCoordinate(mgl32.Vec3{p.vertices[i], p.vertices[i+1], 0}, p.modelMatrix.Mat4)
+		vertices[i] = point.X()
+		vertices[i+1] = point.Y()
+	}
+	// The polygon returned by Points has to match the baked geometry
+	for i, point := range p.polygon {
+		p.polygon[i] = mgl32.TransformCoordinate(point.Vec3(0), p.modelMatrix.Mat4).Vec2()
+	}
+
+	p.position = mgl32.Vec3{0, 0, p.position.Z()}
+	p.scale = mgl32.Vec2{1, 1}
+	p.size = mgl32.Vec2{1, 1}
+	p.anchor = mgl32.Vec2{}
+	p.anchorNormalized = false
+	p.angle = 0
+	p.flipX = false
+	p.flipY = false
+	p.hasRotationPivot = false
+	p.rebuildMatrices()
+
+	arraySize := p.arraySize
+	p.SetVertices(vertices)
+	// Keep a custom array size, the number of vertices hasn't changed
+	p.arraySize = arraySize
+	return nil
+}
+
+// newPrimitive2D creates a primitive with its vertex array already allocated, so all the attributes are set up the
+// same way regardless of the order they are uploaded
+func newPrimitive2D(position mgl32.Vec3, size mgl32.Vec2, shader *ShaderProgram) *Primitive2D {
+	p := &Primitive2D{
+		position: position,
+		size:     size,
+		scale:    mgl32.Vec2{1, 1},
+	}
+	p.shaderProgram = shader
+	p.rebuildMatrices()
+	p.bindVertexArray()
+	glc.BindVertexArray(0)
+	return p
+}
+
 // NewQuadPrimitive creates a rectangular primitive filled with a texture
 func NewQuadPrimitive(position mgl32.Vec3, size mgl32.Vec2) *Primitive2D {
-	shader := NewShaderProgram(VertexShaderBase, "", FragmentShaderTexture)
+	shader := SharedShaderProgram(VertexShaderBase, "", FragmentShaderTexture)
 	return NewQuadPrimitiveExt(position, size, shader, nil, nil)
 }
 
 // NewQuadPrimitiveExt creates a rectangular primitive filled with a texture. It accepts custom shader and coordinates
 func NewQuadPrimitiveExt(position mgl32.Vec3, size mgl32.Vec2, shader *ShaderProgram, vertices []float32, uvCoords []float32) *Primitive2D {
-	q := &Primitive2D{
-		position: position,
-		size:     size,
-		scale:    mgl32.Vec2{1, 1},
-	}
-	q.shaderProgram = shader
-	q.rebuildMatrices()
+	q := newPrimitive2D(position, size, shader)
 	q.arrayMode = gl.TRIANGLE_FAN
 	q.arraySize = 4
 
@@ -205,25 +652,35 @@ func NewQuadPrimitiveExt(position mgl32.Vec3, size mgl32.Vec2, shader *ShaderPro
 	return q
 }
 
-// NewRectPrimitive creates a rectangular primitive
+// NewRectPrimitive creates a rectangular primitive. It returns nil if the size is zero
 func NewRectPrimitive(position mgl32.Vec3, size mgl32.Vec2, filled bool) *Primitive2D {
-	q := &Primitive2D{
-		position: position,
-		size:     size,
-		scale:    mgl32.Vec2{1, 1},
+	if size.X() == 0 || size.Y() == 0 {
+		fmt.Printf("Error: cannot create a rectangle of size %vx%v\n", size.X(), size.Y())
+		return nil
 	}
-	q.shaderProgram = NewShaderProgram(VertexShaderBase, "", FragmentShaderSolidColor)
-	q.rebuildMatrices()
+	q := newPrimitive2D(position, size, SharedShaderProgram(VertexShaderBase, "", FragmentShaderSolidColor))
 
 	if filled {
 		q.arrayMode = gl.TRIANGLE_FAN
-		q.arraySize = 4
 		q.SetVertices([]float32{0, 0, 0, 1, 1, 1, 1, 0})
 	} else {
-		q.arrayMode = gl.LINE_STRIP
-		q.arraySize = 5
-		q.SetVertices([]float32{0, 0, 0, 1, 1, 1, 1, 0, 0, 0})
+		q.arrayMode = gl.LINE_LOOP
+		q.SetVertices([]float32{0, 0, 0, 1, 1, 1, 1, 0})
+	}
+	return q
+}
+
+// NewGradientRectPrimitive creates a filled rectangular primitive interpolating the colors assigned to its corners
+func NewGradientRectPrimitive(position mgl32.Vec3, size mgl32.Vec2, colorTopLeft, colorTopRight, colorBottomRight, colorBottomLeft Color) *Primitive2D {
+	q := newPrimitive2D(position, size, SharedShaderProgram(VertexShaderVertexColor, "", FragmentShaderVertexColor))
+
+	q.arrayMode = gl.TRIANGLE_FAN
+	q.SetVertices([]float32{0, 0, 0, 1, 1, 1, 1, 0})
+	colors := make([]float32, 0, 4*4)
+	for _, c := range []Color{colorTopLeft, colorBottomLeft, colorBottomRight, colorTopRight} {
+		colors = append(colors, c[0], c[1], c[2], c[3])
 	}
+	q.SetVertexColors(colors)
 	return q
 }
 
@@ -235,33 +692,84 @@ func NewRegularPolygonPrimitive(center mgl32.Vec3, radius float32, numSegments i
 		return nil
 	}
 
-	q := &Primitive2D{
-		position: center,
-		size:     mgl32.Vec2{1, 1},
-		scale:    mgl32.Vec2{1, 1},
-	}
-	q.shaderProgram = NewShaderProgram(VertexShaderBase, "", FragmentShaderSolidColor)
-	q.rebuildMatrices()
+	q := newPrimitive2D(center, mgl32.Vec2{1, 1}, SharedShaderProgram(VertexShaderBase, "", FragmentShaderSolidColor))
 
 	// Vertices
 	vertices := make([]float32, 0, numSegments*2)
 	for _, v := range circlePoints {
 		vertices = append(vertices, float32(v[0]), float32(v[1]))
 	}
-	// Add one vertex for the last line
-	vertices = append(vertices, float32(circlePoints[0][0]), float32(circlePoints[0][1]))
 
 	if filled {
 		q.arrayMode = gl.TRIANGLE_FAN
 	} else {
-		q.arrayMode = gl.LINE_STRIP
+		q.arrayMode = gl.LINE_LOOP
 	}
 
+	q.polygon = circlePoints
 	q.SetVertices(vertices)
 	return q
 }
 
-// NewTriangles creates a primitive as a collection of triangles
+// NewStarPrimitive creates a star shaped primitive alternating vertices on the outer and on the inner radius
+func NewStarPrimitive(center mgl32.Vec3, outerRadius, innerRadius float32, numPoints int, filled bool) *Primitive2D {
+	if numPoints < 2 {
+		fmt.Println("numPoints must be >= 2")
+		return nil
+	}
+	if innerRadius < 0 || innerRadius >= outerRadius {
+		fmt.Println("innerRadius must be >= 0 and < outerRadius")
+		return nil
+	}
+
+	q := newPrimitive2D(center, mgl32.Vec2{1, 1}, SharedShaderProgram(VertexShaderBase, "", FragmentShaderSolidColor))
+
+	numVertices := numPoints * 2
+	vertices := make([]float32, 0, (numVertices+2)*2)
+	if filled {
+		// The fan starts from the center of the star
+		vertices = append(vertices, 0, 0)
+	}
+	q.polygon = make([]mgl32.Vec2, 0, numVertices)
+	step := math.Pi / float64(numPoints)
+	for i := 0; i < numVertices; i++ {
+		radius := outerRadius
+		if i%2 == 1 {
+			radius = innerRadius
+		}
+		angle := step * float64(i)
+		point := mgl32.Vec2{radius * float32(math.Cos(angle)), radius * float32(math.Sin(angle))}
+		vertices = append(vertices, point.X(), point.Y())
+		q.polygon = append(q.polygon, point)
+	}
+	if filled {
+		// Add the first point again to close the fan
+		vertices = append(vertices, outerRadius, 0)
+		q.arrayMode = gl.TRIANGLE_FAN
+	} else {
+		q.arrayMode = gl.LINE_LOOP
+	}
+
+	q.SetVertices(vertices)
+	return q
+}
+
+// NewPointsPrimitive creates a primitive drawing a round dot for each point. The points coordinates are in world units
+func NewPointsPrimitive(points []mgl32.Vec2, pointSize float32) *Primitive2D {
+	primitive := newPrimitive2D(mgl32.Vec3{}, mgl32.Vec2{1, 1}, SharedShaderProgram(VertexShaderPoints, "", FragmentShaderRoundPoint))
+	primitive.pointSize = pointSize
+
+	vertices := make([]float32, 0, len(points)*2)
+	for _, p := range points {
+		vertices = append(vertices, p[0], p[1])
+	}
+	primitive.arrayMode = gl.POINTS
+	primitive.SetVertices(vertices)
+	return primitive
+}
+
+// NewTriangles creates a primitive as a collection of triangles. It returns nil if the vertices don't make whole
+// triangles (6 values each)
 func NewTriangles(
 	vertices []float32,
 	uvCoords []float32,
@@ -270,115 +778,460 @@ func NewTriangles(
 	size mgl32.Vec2,
 	shaderProgram *ShaderProgram,
 ) *Primitive2D {
-	p := &Primitive2D{
-		position: position,
-		size:     size,
-		scale:    mgl32.Vec2{1, 1},
+	if len(vertices) == 0 || len(vertices)%6 != 0 {
+		fmt.Printf("Error: cannot create triangles from %d vertex coordinates, a multiple of 6 is needed\n", len(vertices))
+		return nil
 	}
+	p := newPrimitive2D(position, size, shaderProgram)
 	p.arrayMode = gl.TRIANGLES
-	p.arraySize = int32(len(vertices) / 2)
 	p.texture = texture
-	p.shaderProgram = shaderProgram
-	p.rebuildMatrices()
-	gl.GenVertexArrays(1, &p.vaoId)
-	gl.BindVertexArray(p.vaoId)
 	p.SetVertices(vertices)
 	p.SetUVCoords(uvCoords)
-	gl.BindVertexArray(0)
 	return p
 }
 
-// NewPolylinePrimitive creates a primitive from a sequence of points. The points coordinates are relative to the passed center
+// NewTriangleStrip creates a primitive as a strip of triangles, each one made of a vertex and the two preceding it.
+// It returns nil if the vertices don't make at least a triangle or a coordinate is missing
+func NewTriangleStrip(
+	vertices []float32,
+	uvCoords []float32,
+	texture *Texture,
+	position mgl32.Vec3,
+	size mgl32.Vec2,
+	shaderProgram *ShaderProgram,
+) *Primitive2D {
+	if len(vertices) < 6 || len(vertices)%2 != 0 {
+		fmt.Printf("Error: cannot create a triangle strip from %d vertex coordinates, an even number >= 6 is needed\n", len(vertices))
+		return nil
+	}
+	p := newPrimitive2D(position, size, shaderProgram)
+	p.arrayMode = gl.TRIANGLE_STRIP
+	p.texture = texture
+	p.SetVertices(vertices)
+	p.SetUVCoords(uvCoords)
+	return p
+}
+
+// NewRibbonPrimitive creates a strip of the specified width following a path, for trails and beams. The points
+// coordinates are in world units. The U coordinate goes from 0 to 1 along the path, V from 0 to 1 across it
+func NewRibbonPrimitive(points []mgl32.Vec2, width float32) *Primitive2D {
+	// Consecutive duplicated points don't have a direction, skip them
+	path := make([]mgl32.Vec2, 0, len(points))
+	for _, p := range points {
+		if len(path) == 0 || !p.ApproxEqual(path[len(path)-1]) {
+			path = append(path, p)
+		}
+	}
+	if len(path) < 2 {
+		fmt.Println("a ribbon needs at least 2 distinct points")
+		return nil
+	}
+
+	primitive := newPrimitive2D(mgl32.Vec3{}, mgl32.Vec2{1, 1}, SharedShaderProgram(VertexShaderBase, "", FragmentShaderSolidColor))
+
+	lengths := make([]float32, len(path))
+	for i := 1; i < len(path); i++ {
+		lengths[i] = lengths[i-1] + path[i].Sub(path[i-1]).Len()
+	}
+	totalLength := lengths[len(path)-1]
+
+	vertices := make([]float32, 0, len(path)*4)
+	uvCoords := make([]float32, 0, len(path)*4)
+	for i, offset := range miterOffsets(path, width/2) {
+		left := path[i].Add(offset)
+		right := path[i].Sub(offset)
+		u := lengths[i] / totalLength
+		vertices = append(vertices, left.X(), left.Y(), right.X(), right.Y())
+		uvCoords = append(uvCoords, u, 0, u, 1)
+	}
+
+	primitive.arrayMode = gl.TRIANGLE_STRIP
+	primitive.SetVertices(vertices)
+	primitive.SetUVCoords(uvCoords)
+	return primitive
+}
+
+// NewLinePrimitive creates a single segment from start to end. The primitive is positioned at the middle of the
+// segment, so rotating and scaling it keeps the segment centered
+func NewLinePrimitive(start, end mgl32.Vec2) *Primitive2D {
+	center := start.Add(end).Mul(0.5)
+	primitive := newPrimitive2D(center.Vec3(0), mgl32.Vec2{1, 1}, SharedShaderProgram(VertexShaderBase, "", FragmentShaderSolidColor))
+
+	halfSegment := end.Sub(start).Mul(0.5)
+	primitive.arrayMode = gl.LINES
+	primitive.SetVertices([]float32{-halfSegment.X(), -halfSegment.Y(), halfSegment.X(), halfSegment.Y()})
+	return primitive
+}
+
+// NewPolylinePrimitive creates a primitive from a sequence of points. The points coordinates are relative to the passed center.
+// It returns nil if there are fewer than 2 points
 func NewPolylinePrimitive(center mgl32.Vec3, points []mgl32.Vec2, closed bool) *Primitive2D {
-	primitive := &Primitive2D{
-		position: center,
-		size:     mgl32.Vec2{1, 1},
-		scale:    mgl32.Vec2{1, 1},
+	if len(points) < 2 {
+		fmt.Printf("Error: cannot create a polyline from %d points, at least 2 are needed\n", len(points))
+		return nil
 	}
-	primitive.shaderProgram = NewShaderProgram(VertexShaderBase, "", FragmentShaderSolidColor)
-	primitive.rebuildMatrices()
+	primitive := newPrimitive2D(center, mgl32.Vec2{1, 1}, SharedShaderProgram(VertexShaderBase, "", FragmentShaderSolidColor))
 
 	// Vertices
-	var numVertices int32 = int32(len(points))
-	vertices := make([]float32, 0, numVertices*2)
+	vertices := make([]float32, 0, len(points)*2)
 	for _, p := range points {
 		vertices = append(vertices, float32(p[0]), float32(p[1]))
 	}
+
 	if closed {
-		// Add the first point again to close the loop
-		vertices = append(vertices, vertices[0], vertices[1])
-		numVertices++
+		primitive.arrayMode = gl.LINE_LOOP
+	} else {
+		primitive.arrayMode = gl.LINE_STRIP
+	}
+	primitive.SetVertices(vertices)
+	return primitive
+}
+
+// NewConvexHullPrimitive creates a primitive from the convex hull of the points, either as an outline or filled.
+// The points coordinates are relative to the passed center
+func NewConvexHullPrimitive(center mgl32.Vec3, points []mgl32.Vec2, filled bool) (*Primitive2D, error) {
+	hull := ConvexHull(points)
+	if len(hull) < 3 {
+		return nil, errors.New("the convex hull needs at least 3 unique, non collinear, points")
+	}
+
+	primitive := newPrimitive2D(center, mgl32.Vec2{1, 1}, SharedShaderProgram(VertexShaderBase, "", FragmentShaderSolidColor))
+
+	vertices := make([]float32, 0, len(hull)*2)
+	for _, p := range hull {
+		vertices = append(vertices, p[0], p[1])
+	}
+
+	// The hull is convex, a fan from its first vertex covers it
+	if filled {
+		primitive.arrayMode = gl.TRIANGLE_FAN
+	} else {
+		primitive.arrayMode = gl.LINE_LOOP
+	}
+	primitive.polygon = hull
+	primitive.SetVertices(vertices)
+	return primitive, nil
+}
+
+// NewArrowPrimitive creates a filled arrow going from start to end, positioned at start. The head is a triangle
+// pointing to end, if the arrow is shorter than the head only the head is drawn
+func NewArrowPrimitive(start, end mgl32.Vec2, thickness, headLength, headWidth float32) *Primitive2D {
+	direction := end.Sub(start)
+	length := direction.Len()
+	if length == 0 {
+		fmt.Println("the start and the end of an arrow must be different")
+		return nil
+	}
+	direction = direction.Mul(1 / length)
+	normal := mgl32.Vec2{-direction.Y(), direction.X()}
+	if headLength > length {
+		headLength = length
+	}
+
+	primitive := newPrimitive2D(start.Vec3(0), mgl32.Vec2{1, 1}, SharedShaderProgram(VertexShaderBase, "", FragmentShaderSolidColor))
+
+	tip := direction.Mul(length)
+	headBase := direction.Mul(length - headLength)
+	vertices := make([]float32, 0, 18)
+	if headLength < length {
+		halfThickness := normal.Mul(thickness / 2)
+		vertices = appendTriangle(vertices, halfThickness, halfThickness.Mul(-1), headBase.Sub(halfThickness))
+		vertices = appendTriangle(vertices, halfThickness, headBase.Sub(halfThickness), headBase.Add(halfThickness))
 	}
+	halfHead := normal.Mul(headWidth / 2)
+	vertices = appendTriangle(vertices, headBase.Add(halfHead), headBase.Sub(halfHead), tip)
 
-	primitive.arrayMode = gl.LINE_STRIP
-	primitive.arraySize = numVertices
+	primitive.arrayMode = gl.TRIANGLES
 	primitive.SetVertices(vertices)
 	return primitive
 }
 
-//  Creates a grid of lines with a distance of gridSize and filling the area 0,0 -> width,height
+// NewCapsulePrimitive creates a rectangle with semicircular ends on its shorter sides (a stadium). A square size
+// produces a circle. The caps are computed for the initial size, changing it later stretches them
+func NewCapsulePrimitive(position mgl32.Vec3, size mgl32.Vec2, segmentsPerCap int, filled bool) *Primitive2D {
+	if segmentsPerCap < 1 {
+		fmt.Println("segmentsPerCap must be >= 1")
+		return nil
+	}
+	width, height := size.X(), size.Y()
+	if width <= 0 || height <= 0 {
+		fmt.Println("the size of a capsule must be > 0")
+		return nil
+	}
+
+	// The caps are on the shorter sides, angles go clockwise on screen starting from the right
+	var radius float32
+	var centers [2]mgl32.Vec2
+	var startAngles [2]float64
+	if width >= height {
+		radius = height / 2
+		centers = [2]mgl32.Vec2{{width - radius, radius}, {radius, radius}}
+		startAngles = [2]float64{-math.Pi / 2, math.Pi / 2}
+	} else {
+		radius = width / 2
+		centers = [2]mgl32.Vec2{{radius, height - radius}, {radius, radius}}
+		startAngles = [2]float64{0, math.Pi}
+	}
+	outline := make([]mgl32.Vec2, 0, (segmentsPerCap+1)*2)
+	step := math.Pi / float64(segmentsPerCap)
+	for c, center := range centers {
+		for i := 0; i <= segmentsPerCap; i++ {
+			angle := startAngles[c] + step*float64(i)
+			point := center.Add(mgl32.Vec2{radius * float32(math.Cos(angle)), radius * float32(math.Sin(angle))})
+			// With a square size the two caps form a circle and meet on the same points
+			if len(outline) > 0 && point.ApproxEqual(outline[len(outline)-1]) {
+				continue
+			}
+			outline = append(outline, point)
+		}
+	}
+	if outline[len(outline)-1].ApproxEqual(outline[0]) {
+		outline = outline[:len(outline)-1]
+	}
+
+	q := newPrimitive2D(position, size, SharedShaderProgram(VertexShaderBase, "", FragmentShaderSolidColor))
+
+	// The vertices are normalized, the size of the primitive scales them back
+	vertices := make([]float32, 0, (len(outline)+2)*2)
+	if filled {
+		vertices = append(vertices, 0.5, 0.5)
+	}
+	for _, point := range outline {
+		vertices = append(vertices, point.X()/width, point.Y()/height)
+	}
+	if filled {
+		// Add the first point again to close the fan
+		vertices = append(vertices, outline[0].X()/width, outline[0].Y()/height)
+		q.arrayMode = gl.TRIANGLE_FAN
+	} else {
+		q.arrayMode = gl.LINE_LOOP
+	}
+
+	q.SetVertices(vertices)
+	return q
+}
+
+// NewGridPrimitive creates a grid of lines with a distance of gridSize and filling the area 0,0 -> width,height
 func NewGridPrimitive(center mgl32.Vec3, width int, height int, gridSize int) *Primitive2D {
-	primitive := &Primitive2D{
-		position: center,
-		size:     mgl32.Vec2{1, 1},
-		scale:    mgl32.Vec2{1, 1},
+	if gridSize <= 0 {
+		fmt.Println("gridSize must be > 0")
+		return nil
 	}
-	primitive.shaderProgram = NewShaderProgram(VertexShaderBase, "", FragmentShaderSolidColor)
-	primitive.rebuildMatrices()
 
-	var w = width + gridSize;
-	var h = height + gridSize;
-	var numRows = height/gridSize
-	var numColumns = width/gridSize
-	var ox = -width/2
-	var oy = -height/2
+	primitive := newPrimitive2D(center, mgl32.Vec2{1, 1}, SharedShaderProgram(VertexShaderBase, "", FragmentShaderSolidColor))
+	primitive.arrayMode = gl.LINES
+	primitive.SetVertices(gridVertices(width, height, gridSize))
+	return primitive
+}
+
+// gridVertices returns the two ends of each line of the grid, first the horizontal lines and then the vertical ones.
+// The grid is centered on the origin and covers at least width x height, every line ends on the outermost lines
+func gridVertices(width int, height int, gridSize int) []float32 {
+	var numRows = (height+gridSize)/gridSize + 1
+	var numColumns = (width+gridSize)/gridSize + 1
+	var w = (numColumns - 1) * gridSize
+	var h = (numRows - 1) * gridSize
+	var ox = -width / 2
+	var oy = -height / 2
 
-	var numVertices int32 = int32(numRows) * 2 + int32(numColumns)
+	// Two vertices for each horizontal and vertical line
+	var numVertices = (numRows + numColumns) * 2
 	vertices := make([]float32, 0, numVertices*2)
-	for i:=0;i<h / gridSize + 1; i++ {
-		vertices = append(vertices, float32(ox), float32(oy+i *gridSize))
-		vertices = append(vertices, float32(ox + w), float32(oy+i *gridSize))
+	for i := 0; i < numRows; i++ {
+		vertices = append(vertices, float32(ox), float32(oy+i*gridSize))
+		vertices = append(vertices, float32(ox+w), float32(oy+i*gridSize))
 	}
-	for i:=0;i<w / gridSize + 1; i++ {
+	for i := 0; i < numColumns; i++ {
 		vertices = append(vertices, float32(ox+i*gridSize), float32(oy))
 		vertices = append(vertices, float32(ox+i*gridSize), float32(oy+h))
 	}
-
-	primitive.arrayMode = gl.LINES
-	primitive.arraySize = numVertices
-	primitive.SetVertices(vertices)
-	return primitive
+	return vertices
 }
 
 // SetVertices uploads new set of vertices into opengl buffer
 func (p *Primitive2D) SetVertices(vertices []float32) {
-	if p.vaoId == 0 {
-		gl.GenVertexArrays(1, &p.vaoId)
-	}
-	gl.BindVertexArray(p.vaoId)
+	// Without spare capacity AppendVertices can't write into the slice of the caller
+	p.vertices = vertices[:len(vertices):len(vertices)]
+	p.bindVertexArray()
 	if p.vboVertices == 0 {
-		gl.GenBuffers(1, &p.vboVertices)
+		glc.GenBuffers(1, &p.vboVertices)
 	}
-	gl.BindBuffer(gl.ARRAY_BUFFER, p.vboVertices)
-	gl.BufferData(gl.ARRAY_BUFFER, len(vertices)*Float32Size, gl.Ptr(vertices), gl.STATIC_DRAW)
-	gl.EnableVertexAttribArray(0)
-	gl.VertexAttribPointer(0, 2, gl.FLOAT, false, 0, gl.PtrOffset(0))
+	glc.BindBuffer(gl.ARRAY_BUFFER, p.vboVertices)
+	glc.BufferData(gl.ARRAY_BUFFER, len(vertices)*Float32Size, gl.Ptr(vertices), gl.STATIC_DRAW)
+	countBufferUpload()
+	p.verticesCapacity = len(vertices)
+	glc.EnableVertexAttribArray(0)
+	glc.VertexAttribPointer(0, 2, gl.FLOAT, false, 0, gl.PtrOffset(0))
 	p.arraySize = int32(len(vertices) / 2)
-	gl.BindVertexArray(0)
+	glc.BindVertexArray(0)
+}
+
+// AppendVertices adds vertices at the end of the current ones. Only the new vertices are uploaded while the buffer
+// has room for them, otherwise the buffer is reallocated leaving room to grow, so appending many times one point is
+// not quadratic
+func (p *Primitive2D) AppendVertices(vertices []float32) {
+	if len(vertices) == 0 {
+		return
+	}
+	previousLength := len(p.vertices)
+	p.vertices = append(p.vertices, vertices...)
+	p.bindVertexArray()
+	if p.vboVertices == 0 {
+		glc.GenBuffers(1, &p.vboVertices)
+	}
+	glc.BindBuffer(gl.ARRAY_BUFFER, p.vboVertices)
+	if len(p.vertices) <= p.verticesCapacity {
+		glc.BufferSubData(gl.ARRAY_BUFFER, previousLength*Float32Size, len(vertices)*Float32Size, gl.Ptr(vertices))
+	} else {
+		// Allocate as much as the slice capacity, which grows geometrically
+		p.verticesCapacity = cap(p.vertices)
+		glc.BufferData(gl.ARRAY_BUFFER, p.verticesCapacity*Float32Size, nil, gl.DYNAMIC_DRAW)
+		glc.BufferSubData(gl.ARRAY_BUFFER, 0, len(p.vertices)*Float32Size, gl.Ptr(p.vertices))
+		glc.EnableVertexAttribArray(0)
+		glc.VertexAttribPointer(0, 2, gl.FLOAT, false, 0, gl.PtrOffset(0))
+	}
+	countBufferUpload()
+	p.arraySize = int32(len(p.vertices) / 2)
+	glc.BindVertexArray(0)
+}
+
+// VertexCount returns the number of vertices uploaded with SetVertices and AppendVertices
+func (p *Primitive2D) VertexCount() int32 {
+	return int32(len(p.vertices) / 2)
+}
+
+// SetInterleavedVertices uploads positions and, if hasUV is true, UV coordinates in a single buffer with the
+// format [x, y, u, v, x, y, u, v, ...]. The UV flips don't apply to coordinates uploaded this way
+func (p *Primitive2D) SetInterleavedVertices(data []float32, hasUV bool) {
+	layout := VertexLayout{{Location: 0, Components: 2, Type: gl.FLOAT}}
+	if hasUV {
+		stride := int32(4 * Float32Size)
+		layout = VertexLayout{
+			{Location: 0, Components: 2, Type: gl.FLOAT, Stride: stride},
+			{Location: 1, Components: 2, Type: gl.FLOAT, Stride: stride, Offset: 2 * Float32Size},
+		}
+	}
+	if err := p.SetVertexData(data, layout); err != nil {
+		fmt.Println(err)
+	}
 }
 
 // SetUVCoords uploads new UV coordinates
 func (p *Primitive2D) SetUVCoords(uvCoords []float32) {
-	if p.vaoId == 0 {
-		gl.GenVertexArrays(1, &p.vaoId)
+	p.uvCoords = uvCoords
+	p.uploadUVCoords()
+}
+
+// SetFlipUVX mirrors the texture horizontally, swapping the UV coordinates. Unlike SetFlipX the geometry and its
+// winding are untouched, use it when only the image has to be mirrored (e.g. with face culling enabled)
+func (p *Primitive2D) SetFlipUVX(flip bool) {
+	p.flipUVX = flip
+	p.uploadUVCoords()
+}
+
+// SetFlipUVY mirrors the texture vertically, swapping the UV coordinates. Unlike SetFlipY the geometry and its
+// winding are untouched, use it when only the image has to be mirrored (e.g. with face culling enabled)
+func (p *Primitive2D) SetFlipUVY(flip bool) {
+	p.flipUVY = flip
+	p.uploadUVCoords()
+}
+
+// SetUVTransform scales and then offsets the UV coordinates in the vertex shader, e.g. to show a frame of a sprite
+// sheet changing a uniform instead of uploading new UV coordinates. It requires a shader built with
+// VertexShaderUVTransform (or declaring the 'uv_transform' uniform), the other shaders ignore it
+func (p *Primitive2D) SetUVTransform(offset mgl32.Vec2, scale mgl32.Vec2) {
+	uvTransform := mgl32.Translate2D(offset.X(), offset.Y()).Mul3(mgl32.Scale2D(scale.X(), scale.Y()))
+	p.uvTransform = &uvTransform
+}
+
+// ClearUVTransform removes the transformation set with SetUVTransform
+func (p *Primitive2D) ClearUVTransform() {
+	p.uvTransform = nil
+}
+
+func (p *Primitive2D) uploadUVCoords() {
+	if p.uvCoords == nil {
+		return
 	}
-	gl.BindVertexArray(p.vaoId)
+	uvCoords := p.drawUVCoords()
+
+	p.bindVertexArray()
 	if p.vboUVCoords == 0 {
-		gl.GenBuffers(1, &p.vboUVCoords)
+		glc.GenBuffers(1, &p.vboUVCoords)
+	}
+	glc.BindBuffer(gl.ARRAY_BUFFER, p.vboUVCoords)
+	glc.BufferData(gl.ARRAY_BUFFER, len(uvCoords)*Float32Size, gl.Ptr(uvCoords), gl.STATIC_DRAW)
+	countBufferUpload()
+	glc.EnableVertexAttribArray(1)
+	glc.VertexAttribPointer(1, 2, gl.FLOAT, false, 0, gl.PtrOffset(0))
+	glc.BindVertexArray(0)
+}
+
+// drawUVCoords returns the UV coordinates the primitive is drawn with, flipped and tiled
+func (p *Primitive2D) drawUVCoords() []float32 {
+	uvCoords := p.uvCoords
+	if p.flipUVX || p.flipUVY {
+		// Mirror the coordinates inside their bounding box, so it works for texture regions too
+		points := make([]mgl32.Vec2, 0, len(uvCoords)/2)
+		for i := 0; i+1 < len(uvCoords); i += 2 {
+			points = append(points, mgl32.Vec2{uvCoords[i], uvCoords[i+1]})
+		}
+		min, max := GetBoundingBox(points)
+		uvCoords = make([]float32, len(p.uvCoords))
+		copy(uvCoords, p.uvCoords)
+		for i := 0; i+1 < len(uvCoords); i += 2 {
+			if p.flipUVX {
+				uvCoords[i] = min.X() + max.X() - uvCoords[i]
+			}
+			if p.flipUVY {
+				uvCoords[i+1] = min.Y() + max.Y() - uvCoords[i+1]
+			}
+		}
+	}
+	if p.tiling != nil {
+		uvCoords = p.tileUVCoords(uvCoords)
+	}
+	return uvCoords
+}
+
+// SetVertexColors uploads a RGBA color for each vertex
+func (p *Primitive2D) SetVertexColors(colors []float32) {
+	p.bindVertexArray()
+	if p.vboColors == 0 {
+		glc.GenBuffers(1, &p.vboColors)
+	}
+	glc.BindBuffer(gl.ARRAY_BUFFER, p.vboColors)
+	glc.BufferData(gl.ARRAY_BUFFER, len(colors)*Float32Size, gl.Ptr(colors), gl.STATIC_DRAW)
+	countBufferUpload()
+	glc.EnableVertexAttribArray(2)
+	glc.VertexAttribPointer(2, 4, gl.FLOAT, false, 0, gl.PtrOffset(0))
+	glc.BindVertexArray(0)
+}
+
+// SetUVCoords2 uploads a second set of UV coordinates (attribute 3), used for example by lightmaps and detail textures
+func (p *Primitive2D) SetUVCoords2(uvCoords []float32) {
+	p.bindVertexArray()
+	if p.vboUVCoords2 == 0 {
+		glc.GenBuffers(1, &p.vboUVCoords2)
+	}
+	glc.BindBuffer(gl.ARRAY_BUFFER, p.vboUVCoords2)
+	glc.BufferData(gl.ARRAY_BUFFER, len(uvCoords)*Float32Size, gl.Ptr(uvCoords), gl.STATIC_DRAW)
+	countBufferUpload()
+	glc.EnableVertexAttribArray(3)
+	glc.VertexAttribPointer(3, 2, gl.FLOAT, false, 0, gl.PtrOffset(0))
+	glc.BindVertexArray(0)
+}
+
+// SetTextureLayers uploads the index of the texture array layer for each vertex (attribute 4)
+func (p *Primitive2D) SetTextureLayers(layers []float32) {
+	p.bindVertexArray()
+	if p.vboLayers == 0 {
+		glc.GenBuffers(1, &p.vboLayers)
 	}
-	gl.BindBuffer(gl.ARRAY_BUFFER, p.vboUVCoords)
-	gl.BufferData(gl.ARRAY_BUFFER, len(uvCoords)*Float32Size, gl.Ptr(uvCoords), gl.STATIC_DRAW)
-	gl.EnableVertexAttribArray(1)
-	gl.VertexAttribPointer(1, 2, gl.FLOAT, false, 0, gl.PtrOffset(0))
-	gl.BindVertexArray(0)
+	glc.BindBuffer(gl.ARRAY_BUFFER, p.vboLayers)
+	glc.BufferData(gl.ARRAY_BUFFER, len(layers)*Float32Size, gl.Ptr(layers), gl.STATIC_DRAW)
+	countBufferUpload()
+	glc.EnableVertexAttribArray(4)
+	glc.VertexAttribPointer(4, 1, gl.FLOAT, false, 0, gl.PtrOffset(0))
+	glc.BindVertexArray(0)
 }
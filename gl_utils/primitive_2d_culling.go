@@ -0,0 +1,96 @@
+package gl_utils
+
+import (
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// CullMode which faces of a primitive are discarded when it's drawn
+type CullMode int
+
+// Cull modes supported
+const (
+	// CullDefault leaves face culling as it's currently set in OpenGL
+	CullDefault CullMode = iota
+	// CullNone draws both the front and the back faces
+	CullNone
+	// CullBack discards the back faces
+	CullBack
+	// CullFront discards the front faces
+	CullFront
+)
+
+// faceCullingState the OpenGL culling state to restore after a primitive has been drawn
+type faceCullingState struct {
+	changed   bool
+	enabled   bool
+	cullFace  int32
+	frontFace int32
+}
+
+// CullMode returns the face culling mode used to draw the primitive
+func (p *Primitive2D) CullMode() CullMode {
+	return p.cullMode
+}
+
+// SetCullMode sets the face culling mode used to draw the primitive. With CullDefault the current OpenGL state is
+// used, the other modes are applied only while the primitive is drawn
+func (p *Primitive2D) SetCullMode(mode CullMode) {
+	p.cullMode = mode
+}
+
+// SetFrontFace sets whether the front faces of the primitive have their vertices in counter-clockwise (the default)
+// or in clockwise order. The order is automatically reversed while the primitive is mirrored by flips or by a
+// negative scale on exactly one axis
+func (p *Primitive2D) SetFrontFace(ccw bool) {
+	p.frontFaceCW = !ccw
+}
+
+// mirrored returns true when the transformation of the primitive reverses the winding of its vertices
+func (p *Primitive2D) mirrored() bool {
+	negativeX := (p.scale.X() < 0) != p.flipX
+	negativeY := (p.scale.Y() < 0) != p.flipY
+	return negativeX != negativeY
+}
+
+// applyFaceCulling sets the OpenGL culling state for the primitive and returns the previous one
+func (p *Primitive2D) applyFaceCulling() faceCullingState {
+	clockwise := p.frontFaceCW != p.mirrored()
+	if p.cullMode == CullDefault && !clockwise {
+		return faceCullingState{}
+	}
+
+	previous := faceCullingState{changed: true, enabled: glc.IsEnabled(gl.CULL_FACE)}
+	glc.GetIntegerv(gl.CULL_FACE_MODE, &previous.cullFace)
+	glc.GetIntegerv(gl.FRONT_FACE, &previous.frontFace)
+
+	switch p.cullMode {
+	case CullNone:
+		glc.Disable(gl.CULL_FACE)
+	case CullBack:
+		glc.Enable(gl.CULL_FACE)
+		glc.CullFace(gl.BACK)
+	case CullFront:
+		glc.Enable(gl.CULL_FACE)
+		glc.CullFace(gl.FRONT)
+	}
+	if clockwise {
+		glc.FrontFace(gl.CW)
+	} else {
+		glc.FrontFace(gl.CCW)
+	}
+	return previous
+}
+
+// restoreFaceCulling restores the state returned by applyFaceCulling
+func restoreFaceCulling(state faceCullingState) {
+	if !state.changed {
+		return
+	}
+	if state.enabled {
+		glc.Enable(gl.CULL_FACE)
+	} else {
+		glc.Disable(gl.CULL_FACE)
+	}
+	glc.CullFace(uint32(state.cullFace))
+	glc.FrontFace(uint32(state.frontFace))
+}
@@ -0,0 +1,69 @@
+package gl_utils
+
+import (
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// depthSettings how a primitive uses the depth buffer. Unless they are set, the current OpenGL state is used
+type depthSettings struct {
+	testSet  bool
+	test     bool
+	writeSet bool
+	write    bool
+}
+
+// depthState the OpenGL depth state to restore after a primitive has been drawn
+type depthState struct {
+	testChanged  bool
+	test         bool
+	writeChanged bool
+	write        bool
+}
+
+// SetDepthTest sets whether the primitive is tested against the depth buffer while it's drawn (e.g. disabled for
+// UI elements, enabled for world sprites). Until it's called the current OpenGL state is used
+func (p *Primitive2D) SetDepthTest(enabled bool) {
+	p.depth.testSet = true
+	p.depth.test = enabled
+}
+
+// SetDepthWrite sets whether the primitive writes into the depth buffer while it's drawn. Until it's called the
+// current OpenGL state is used
+func (p *Primitive2D) SetDepthWrite(enabled bool) {
+	p.depth.writeSet = true
+	p.depth.write = enabled
+}
+
+// applyDepthSettings sets the OpenGL depth state for the primitive and returns the previous one
+func (p *Primitive2D) applyDepthSettings() depthState {
+	var previous depthState
+	if p.depth.testSet {
+		previous.testChanged = true
+		previous.test = glc.IsEnabled(gl.DEPTH_TEST)
+		if p.depth.test {
+			glc.Enable(gl.DEPTH_TEST)
+		} else {
+			glc.Disable(gl.DEPTH_TEST)
+		}
+	}
+	if p.depth.writeSet {
+		previous.writeChanged = true
+		glc.GetBooleanv(gl.DEPTH_WRITEMASK, &previous.write)
+		glc.DepthMask(p.depth.write)
+	}
+	return previous
+}
+
+// restoreDepthState restores the state returned by applyDepthSettings
+func restoreDepthState(state depthState) {
+	if state.testChanged {
+		if state.test {
+			glc.Enable(gl.DEPTH_TEST)
+		} else {
+			glc.Disable(gl.DEPTH_TEST)
+		}
+	}
+	if state.writeChanged {
+		glc.DepthMask(state.write)
+	}
+}
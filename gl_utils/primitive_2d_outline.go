@@ -0,0 +1,97 @@
+package gl_utils
+
+import (
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// primitiveOutline an outline drawn behind a filled primitive
+type primitiveOutline struct {
+	width         float32
+	color         Color
+	shaderProgram *ShaderProgram
+	// Used instead of shaderProgram by DrawWithView
+	viewShaderProgram *ShaderProgram
+}
+
+// SetOutline draws an outline of the specified width (in pixels of the primitive, before scaling) and color around
+// the primitive. Pass a width of 0 to remove it.
+// The outline is a copy of the primitive filled with the color, enlarged around the center of its bounding box and
+// drawn before it. It's cheap and accurate for convex shapes centered on their bounding box (rectangles, circles,
+// regular polygons). With concave shapes, or shapes whose bounding box is far from symmetric, the outline gets
+// uneven or misses parts. It applies only to filled primitives (triangles, strips and fans)
+func (p *Primitive2D) SetOutline(width float32, color Color) {
+	if width <= 0 {
+		p.outline = nil
+		return
+	}
+	if p.outline == nil {
+		p.outline = &primitiveOutline{
+			shaderProgram: SharedShaderProgram(VertexShaderBase, "", FragmentShaderSolidColor),
+		}
+	}
+	p.outline.width = width
+	p.outline.color = color
+}
+
+// Outline returns the width and the color of the outline, the width is 0 if there is no outline
+func (p *Primitive2D) Outline() (float32, Color) {
+	if p.outline == nil {
+		return 0, Color{}
+	}
+	return p.outline.width, p.outline.color
+}
+
+// drawOutline draws the enlarged copy of the primitive with the model matrix passed, if an outline has been set
+func (p *Primitive2D) drawOutline(projectionMatrix *mgl32.Mat4, viewMatrix *mgl32.Mat4, model mgl32.Mat4) {
+	if p.outline == nil {
+		return
+	}
+	switch p.arrayMode {
+	case gl.TRIANGLES, gl.TRIANGLE_STRIP, gl.TRIANGLE_FAN:
+	default:
+		return
+	}
+
+	vertices := p.vertices
+	if len(vertices) == 0 {
+		vertices = []float32{0, 0, 1, 1}
+	}
+	points := make([]mgl32.Vec2, 0, len(vertices)/2)
+	for i := 0; i+1 < len(vertices); i += 2 {
+		points = append(points, mgl32.Vec2{vertices[i], vertices[i+1]})
+	}
+	min, max := GetBoundingBox(points)
+	center := min.Add(max).Mul(0.5)
+	// The vertices are multiplied by the size, the width of the outline is not
+	extent := mgl32.Vec2{(max.X() - min.X()) * p.size.X(), (max.Y() - min.Y()) * p.size.Y()}
+	scaleX, scaleY := float32(1), float32(1)
+	if extent.X() != 0 {
+		scaleX = (extent.X() + p.outline.width*2) / extent.X()
+	}
+	if extent.Y() != 0 {
+		scaleY = (extent.Y() + p.outline.width*2) / extent.Y()
+	}
+	enlarge := mgl32.Translate3D(center.X(), center.Y(), 0).
+		Mul4(mgl32.Scale3D(scaleX, scaleY, 1)).
+		Mul4(mgl32.Translate3D(-center.X(), -center.Y(), 0))
+	model = model.Mul4(enlarge)
+
+	shader := p.outline.shaderProgram
+	if viewMatrix != nil {
+		if p.outline.viewShaderProgram == nil {
+			p.outline.viewShaderProgram = SharedShaderProgram(VertexShaderView, "", FragmentShaderSolidColor)
+		}
+		shader = p.outline.viewShaderProgram
+	}
+	glc.UseProgram(shader.ID())
+	shader.SetUniform("projection", projectionMatrix)
+	if viewMatrix != nil {
+		shader.SetUniform("view", viewMatrix)
+	}
+	shader.SetUniform("model", &model)
+	shader.SetUniform("color", &p.outline.color)
+	glc.BindVertexArray(p.vaoId)
+	glc.DrawArrays(p.arrayMode, 0, p.arraySize)
+	countDrawCall(int(p.arraySize))
+}
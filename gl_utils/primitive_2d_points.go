@@ -0,0 +1,42 @@
+package gl_utils
+
+import (
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// PointSizeMode the unit of the size of the points of a points primitive
+type PointSizeMode int
+
+// Point size modes supported
+const (
+	// PointSizeScreen keeps the points the same size in pixels at any zoom level
+	PointSizeScreen PointSizeMode = iota
+	// PointSizeWorld expresses the size in world units, the points grow and shrink with the zoom
+	PointSizeWorld
+)
+
+// PointSizeMode returns the unit of the size of the points
+func (p *Primitive2D) PointSizeMode() PointSizeMode {
+	return p.pointSizeMode
+}
+
+// SetPointSizeMode sets the unit of the size of the points (PointSizeScreen by default). In PointSizeWorld mode the
+// size is converted to pixels from the horizontal scale of the projection (and view) matrix and the current viewport
+func (p *Primitive2D) SetPointSizeMode(mode PointSizeMode) {
+	p.pointSizeMode = mode
+}
+
+// setWorldPointSize sets the 'point_size' uniform to the size in pixels of the points in PointSizeWorld mode
+func (p *Primitive2D) setWorldPointSize(projectionMatrix *mgl32.Mat4, viewMatrix *mgl32.Mat4) {
+	matrix := *projectionMatrix
+	if viewMatrix != nil {
+		matrix = matrix.Mul4(*viewMatrix)
+	}
+	var viewport [4]int32
+	glc.GetIntegerv(gl.VIEWPORT, &viewport[0])
+	// Length in normalized device coordinates of a world unit along X, NDC go from -1 to 1 across the viewport
+	unit := mgl32.Vec2{matrix[0], matrix[1]}.Len()
+	size := p.pointSize * unit * float32(viewport[2]) / 2
+	p.shaderProgram.SetUniform("point_size", &size)
+}
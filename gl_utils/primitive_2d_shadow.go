@@ -0,0 +1,128 @@
+package gl_utils
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// primitiveShadow a blurred copy of the primitive drawn behind it
+type primitiveShadow struct {
+	offset mgl32.Vec2
+	blur   float32
+	color  Color
+	// Shaders indexed by the use of a view matrix (see DrawWithView)
+	solidShaders   [2]*ShaderProgram
+	textureShaders [2]*ShaderProgram
+}
+
+// SetShadow draws a copy of the primitive filled with the color, moved by offset (in world units) and blurred, behind
+// the primitive. Pass a color with alpha 0 to remove it.
+// Shapes drawn with a distance field shader (e.g. SmoothCirclePrimitive, SmoothRoundedRectPrimitive) use the field
+// softened by blur model units. Textured primitives use the alpha of the texture, blurred by blur texels in the
+// shader. Other primitives get a
+// sharp silhouette, blur is ignored. The blur can't spread the shadow beyond the geometry of the primitive
+func (p *Primitive2D) SetShadow(offset mgl32.Vec2, blur float32, color Color) {
+	if color.A() <= 0 {
+		p.shadow = nil
+		return
+	}
+	if p.shadow == nil {
+		p.shadow = &primitiveShadow{}
+	}
+	p.shadow.offset = offset
+	p.shadow.blur = blur
+	p.shadow.color = color
+}
+
+// RemoveShadow removes the shadow set with SetShadow
+func (p *Primitive2D) RemoveShadow() {
+	p.shadow = nil
+}
+
+// Shadow returns the offset, the blur and the color of the shadow. The last value is false if there is no shadow
+func (p *Primitive2D) Shadow() (mgl32.Vec2, float32, Color, bool) {
+	if p.shadow == nil {
+		return mgl32.Vec2{}, 0, Color{}, false
+	}
+	return p.shadow.offset, p.shadow.blur, p.shadow.color, true
+}
+
+// drawShadow draws the shadow of the primitive with the model matrix passed, if one has been set
+func (p *Primitive2D) drawShadow(projectionMatrix *mgl32.Mat4, viewMatrix *mgl32.Mat4, model mgl32.Mat4) {
+	if p.shadow == nil {
+		return
+	}
+	viewIndex := 0
+	if viewMatrix != nil {
+		viewIndex = 1
+	}
+
+	var shader *ShaderProgram
+	switch {
+	case p.material != nil && p.material.shaderProgram.GetUniform("softness") >= 0:
+		// A distance field shape, the shadow is the same shape with a softer edge
+		p.material.Apply()
+		shader = p.material.shaderProgram
+		softness := p.shadow.blur
+		shader.SetUniform("softness", &softness)
+	case p.texture != nil:
+		if p.shadow.textureShaders[viewIndex] == nil {
+			p.shadow.textureShaders[viewIndex] = SharedShaderProgram(shadowVertexShaders[viewIndex], "", FragmentShaderShadowTexture)
+		}
+		shader = p.shadow.textureShaders[viewIndex]
+		glc.UseProgram(shader.ID())
+		p.texture.BindToUnit(0)
+		shader.SetUniform("blur", &p.shadow.blur)
+	default:
+		if p.shadow.solidShaders[viewIndex] == nil {
+			p.shadow.solidShaders[viewIndex] = SharedShaderProgram(shadowVertexShaders[viewIndex], "", FragmentShaderSolidColor)
+		}
+		shader = p.shadow.solidShaders[viewIndex]
+		glc.UseProgram(shader.ID())
+	}
+
+	model = mgl32.Translate3D(p.shadow.offset.X(), p.shadow.offset.Y(), 0).Mul4(model)
+	shader.SetUniform("projection", projectionMatrix)
+	if viewMatrix != nil {
+		shader.SetUniform("view", viewMatrix)
+	}
+	shader.SetUniform("model", &model)
+	shader.SetUniform("color", &p.shadow.color)
+	blendState := p.applyBlendMode()
+	glc.BindVertexArray(p.vaoId)
+	glc.DrawArrays(p.arrayMode, 0, p.arraySize)
+	countDrawCall(int(p.arraySize))
+	restoreBlendState(blendState)
+}
+
+// Vertex shaders of the shadow, without and with a view matrix
+var shadowVertexShaders = [2]string{VertexShaderBase, VertexShaderView}
+
+const (
+	// FragmentShaderShadowTexture fills the primitive with the 'color' uniform, modulated by the alpha of the texture
+	// averaged over 'blur' texels around each pixel
+	FragmentShaderShadowTexture = `
+        #version 410 core
+
+        in vec2 uv_out;
+        out vec4 out_color;
+        uniform vec4 color;
+        uniform float blur;
+
+        uniform sampler2D tex;
+
+        // Samples taken on each side of the pixel
+        const int samples = 2;
+
+        void main() {
+            vec2 spacing = blur / float(samples) / vec2(textureSize(tex, 0));
+            float alpha = 0.0;
+            for (int x = -samples; x <= samples; x++) {
+                for (int y = -samples; y <= samples; y++) {
+                    alpha += texture(tex, uv_out + vec2(x, y) * spacing).a;
+                }
+            }
+            alpha /= float((samples * 2 + 1) * (samples * 2 + 1));
+            out_color = vec4(color.rgb, color.a * alpha);
+        }
+        ` + "\x00"
+)
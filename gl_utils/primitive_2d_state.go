@@ -0,0 +1,72 @@
+package gl_utils
+
+import (
+	"encoding/json"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Primitive2DState the transformation and the color of a primitive. Geometry, shader and textures are not part of
+// the state and have to be attached again after restoring it
+type Primitive2DState struct {
+	Position mgl32.Vec3 `json:"position"`
+	Scale    mgl32.Vec2 `json:"scale"`
+	Size     mgl32.Vec2 `json:"size"`
+	Anchor   mgl32.Vec2 `json:"anchor"`
+	// NormalizedAnchor is set when the anchor was specified with SetAnchorNormalized
+	NormalizedAnchor *mgl32.Vec2 `json:"normalized_anchor,omitempty"`
+	Angle            float32     `json:"angle"`
+	FlipX            bool        `json:"flip_x"`
+	FlipY            bool        `json:"flip_y"`
+	Color            Color       `json:"color"`
+}
+
+// State returns the current transformation and color of the primitive
+func (p *Primitive2D) State() Primitive2DState {
+	state := Primitive2DState{
+		Position: p.position,
+		Scale:    p.scale,
+		Size:     p.size,
+		Anchor:   p.anchor,
+		Angle:    p.angle,
+		FlipX:    p.flipX,
+		FlipY:    p.flipY,
+		Color:    p.Color(),
+	}
+	if p.anchorNormalized {
+		normalizedAnchor := p.normalizedAnchor
+		state.NormalizedAnchor = &normalizedAnchor
+	}
+	return state
+}
+
+// SetState restores the transformation and color of the primitive
+func (p *Primitive2D) SetState(state Primitive2DState) {
+	p.SetPosition(state.Position)
+	p.SetSize(state.Size)
+	if state.NormalizedAnchor != nil {
+		p.SetAnchorNormalized(state.NormalizedAnchor.X(), state.NormalizedAnchor.Y())
+	} else {
+		p.SetAnchor(state.Anchor)
+	}
+	p.SetAngle(state.Angle)
+	p.flipX = state.FlipX
+	p.flipY = state.FlipY
+	p.SetScale(state.Scale)
+	p.SetColor(state.Color)
+}
+
+// MarshalJSON encodes the state of the primitive (see Primitive2DState)
+func (p *Primitive2D) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.State())
+}
+
+// UnmarshalJSON restores the state of the primitive (see Primitive2DState)
+func (p *Primitive2D) UnmarshalJSON(data []byte) error {
+	state := p.State()
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	}
+	p.SetState(state)
+	return nil
+}
@@ -0,0 +1,336 @@
+package gl_utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestConstructorsValidateInput(t *testing.T) {
+	useRecordingGL(t)
+	shader := SharedShaderProgram(VertexShaderBase, "", FragmentShaderSolidColor)
+	tests := []struct {
+		name  string
+		build func() *Primitive2D
+		valid bool
+	}{
+		{"rect of zero width", func() *Primitive2D {
+			return NewRectPrimitive(mgl32.Vec3{}, mgl32.Vec2{0, 10}, true)
+		}, false},
+		{"rect of zero height", func() *Primitive2D {
+			return NewRectPrimitive(mgl32.Vec3{}, mgl32.Vec2{10, 0}, false)
+		}, false},
+		{"rect", func() *Primitive2D {
+			return NewRectPrimitive(mgl32.Vec3{}, mgl32.Vec2{10, 10}, true)
+		}, true},
+		{"polyline without points", func() *Primitive2D {
+			return NewPolylinePrimitive(mgl32.Vec3{}, nil, false)
+		}, false},
+		{"polyline of one point", func() *Primitive2D {
+			return NewPolylinePrimitive(mgl32.Vec3{}, []mgl32.Vec2{{1, 1}}, true)
+		}, false},
+		{"polyline", func() *Primitive2D {
+			return NewPolylinePrimitive(mgl32.Vec3{}, []mgl32.Vec2{{0, 0}, {1, 1}}, false)
+		}, true},
+		{"triangles without vertices", func() *Primitive2D {
+			return NewTriangles(nil, nil, nil, mgl32.Vec3{}, mgl32.Vec2{1, 1}, shader)
+		}, false},
+		{"triangles with a partial triangle", func() *Primitive2D {
+			return NewTriangles([]float32{0, 0, 1, 0, 0, 1, 1, 1}, nil, nil, mgl32.Vec3{}, mgl32.Vec2{1, 1}, shader)
+		}, false},
+		{"triangles", func() *Primitive2D {
+			return NewTriangles([]float32{0, 0, 1, 0, 0, 1}, nil, nil, mgl32.Vec3{}, mgl32.Vec2{1, 1}, shader)
+		}, true},
+		{"triangle strip of two vertices", func() *Primitive2D {
+			return NewTriangleStrip([]float32{0, 0, 1, 0}, nil, nil, mgl32.Vec3{}, mgl32.Vec2{1, 1}, shader)
+		}, false},
+		{"triangle strip with a missing coordinate", func() *Primitive2D {
+			return NewTriangleStrip([]float32{0, 0, 1, 0, 0, 1, 1}, nil, nil, mgl32.Vec3{}, mgl32.Vec2{1, 1}, shader)
+		}, false},
+		{"triangle strip", func() *Primitive2D {
+			return NewTriangleStrip([]float32{0, 0, 1, 0, 0, 1, 1, 1}, nil, nil, mgl32.Vec3{}, mgl32.Vec2{1, 1}, shader)
+		}, true},
+	}
+	for _, test := range tests {
+		p := test.build()
+		if test.valid && p == nil {
+			t.Errorf("%s: unexpected nil primitive", test.name)
+		}
+		if !test.valid && p != nil {
+			t.Errorf("%s: expected nil, got a primitive", test.name)
+		}
+	}
+}
+
+func TestGridVertices(t *testing.T) {
+	tests := []struct {
+		width, height, gridSize int
+		rows, columns           int
+	}{
+		{100, 100, 10, 12, 12},
+		{100, 50, 25, 4, 6},
+		{25, 35, 10, 5, 4},
+		{7, 3, 10, 2, 2},
+		{0, 0, 5, 2, 2},
+	}
+	for _, test := range tests {
+		vertices := gridVertices(test.width, test.height, test.gridSize)
+		numLines := test.rows + test.columns
+		if len(vertices) != numLines*4 {
+			t.Errorf("%dx%d/%d: %d vertex coordinates, expected %d", test.width, test.height, test.gridSize, len(vertices), numLines*4)
+			continue
+		}
+		left := float32(-test.width / 2)
+		top := float32(-test.height / 2)
+		right := left + float32((test.columns-1)*test.gridSize)
+		bottom := top + float32((test.rows-1)*test.gridSize)
+		if right-left < float32(test.width) || bottom-top < float32(test.height) {
+			t.Errorf("%dx%d/%d: the grid %v,%v - %v,%v doesn't cover the area", test.width, test.height, test.gridSize, left, top, right, bottom)
+		}
+		for i := 0; i < test.rows; i++ {
+			y := top + float32(i*test.gridSize)
+			line := vertices[i*4 : i*4+4]
+			if line[0] != left || line[1] != y || line[2] != right || line[3] != y {
+				t.Errorf("%dx%d/%d: row %d is %v, expected %v,%v - %v,%v", test.width, test.height, test.gridSize, i, line, left, y, right, y)
+			}
+		}
+		for i := 0; i < test.columns; i++ {
+			x := left + float32(i*test.gridSize)
+			line := vertices[(test.rows+i)*4 : (test.rows+i)*4+4]
+			if line[0] != x || line[1] != top || line[2] != x || line[3] != bottom {
+				t.Errorf("%dx%d/%d: column %d is %v, expected %v,%v - %v,%v", test.width, test.height, test.gridSize, i, line, x, top, x, bottom)
+			}
+		}
+	}
+}
+
+func TestNewGridPrimitive(t *testing.T) {
+	useRecordingGL(t)
+	if NewGridPrimitive(mgl32.Vec3{}, 100, 100, 0) != nil {
+		t.Error("a grid with gridSize 0 has been created")
+	}
+	grid := NewGridPrimitive(mgl32.Vec3{}, 25, 35, 10)
+	if grid.ArraySize() != (5+4)*2 {
+		t.Errorf("ArraySize() = %d, expected %d", grid.ArraySize(), (5+4)*2)
+	}
+}
+
+// checkVertexAttributes checks that the vertex and UV attributes of the primitive are enabled in its vertex array
+// and read from its buffers
+func checkVertexAttributes(t *testing.T, r *recordingGLContext, name string, p *Primitive2D) {
+	t.Helper()
+	expected := map[uint32]recordedAttribute{
+		0: {enabled: true, buffer: p.vboVertices, size: 2},
+		1: {enabled: true, buffer: p.vboUVCoords, size: 2},
+	}
+	for index, attribute := range expected {
+		recorded := r.attributes[p.vaoId][index]
+		if recorded == nil || *recorded != attribute {
+			t.Errorf("%s: attribute %d is %+v, expected %+v", name, index, recorded, attribute)
+		}
+	}
+	if r.boundVertexArray != 0 {
+		t.Errorf("%s: the vertex array %d is still bound", name, r.boundVertexArray)
+	}
+}
+
+func TestVertexAttributesSetup(t *testing.T) {
+	r := useRecordingGL(t)
+	shader := SharedShaderProgram(VertexShaderBase, "", FragmentShaderTexture)
+	vertices := []float32{0, 0, 0, 1, 1, 1}
+	uvCoords := []float32{0, 0, 0, 1, 1, 1}
+
+	quad := NewQuadPrimitive(mgl32.Vec3{}, mgl32.Vec2{10, 10})
+	checkVertexAttributes(t, r, "NewQuadPrimitive", quad)
+
+	triangles := NewTriangles(vertices, uvCoords, nil, mgl32.Vec3{}, mgl32.Vec2{1, 1}, shader)
+	checkVertexAttributes(t, r, "NewTriangles", triangles)
+
+	// The attributes don't depend on the order of the uploads
+	reversed := newPrimitive2D(mgl32.Vec3{}, mgl32.Vec2{1, 1}, shader)
+	reversed.SetUVCoords(uvCoords)
+	reversed.SetVertices(vertices)
+	checkVertexAttributes(t, r, "UVs before vertices", reversed)
+
+	// Each primitive creates a single vertex array
+	count := 0
+	for _, call := range r.calls {
+		if strings.HasPrefix(call, "GenVertexArrays(") {
+			count++
+		}
+	}
+	if count != 3 {
+		t.Errorf("%d vertex arrays created for 3 primitives", count)
+	}
+}
+
+func TestClosedOutlinesUseLineLoop(t *testing.T) {
+	useRecordingGL(t)
+	square := []mgl32.Vec2{{0, 0}, {0, 1}, {1, 1}, {1, 0}}
+	tests := []struct {
+		name        string
+		primitive   *Primitive2D
+		arrayMode   uint32
+		numVertices int32
+	}{
+		{"closed polyline", NewPolylinePrimitive(mgl32.Vec3{}, square, true), gl.LINE_LOOP, 4},
+		{"open polyline", NewPolylinePrimitive(mgl32.Vec3{}, square, false), gl.LINE_STRIP, 4},
+		{"polygon outline", NewRegularPolygonPrimitive(mgl32.Vec3{}, 10, 12, false), gl.LINE_LOOP, 12},
+		{"filled polygon", NewRegularPolygonPrimitive(mgl32.Vec3{}, 10, 12, true), gl.TRIANGLE_FAN, 12},
+		{"rect outline", NewRectPrimitive(mgl32.Vec3{}, mgl32.Vec2{5, 5}, false), gl.LINE_LOOP, 4},
+		{"filled rect", NewRectPrimitive(mgl32.Vec3{}, mgl32.Vec2{5, 5}, true), gl.TRIANGLE_FAN, 4},
+	}
+	for _, test := range tests {
+		p := test.primitive
+		if p.ArrayMode() != test.arrayMode {
+			t.Errorf("%s: array mode %d, expected %d", test.name, p.ArrayMode(), test.arrayMode)
+		}
+		if p.ArraySize() != test.numVertices || len(p.vertices) != int(test.numVertices)*2 {
+			t.Errorf("%s: %d vertices drawn out of %d, expected %d", test.name, p.ArraySize(), len(p.vertices)/2, test.numVertices)
+		}
+		// The loop is closed by OpenGL, the first vertex isn't repeated
+		last := len(p.vertices) - 2
+		if p.vertices[0] == p.vertices[last] && p.vertices[1] == p.vertices[last+1] {
+			t.Errorf("%s: the first vertex is repeated at the end", test.name)
+		}
+	}
+}
+
+func TestBakeTransformPolygon(t *testing.T) {
+	useRecordingGL(t)
+	p := NewRegularPolygonPrimitive(mgl32.Vec3{10, 20, 0}, 5, 6, false)
+	p.SetScale(mgl32.Vec2{2, 3})
+	p.SetAngle(0.5)
+	if err := p.BakeTransform(); err != nil {
+		t.Fatal(err)
+	}
+	points := p.Points()
+	if len(points)*2 != len(p.vertices) {
+		t.Fatalf("%d points for %d vertices", len(points), len(p.vertices)/2)
+	}
+	for i, point := range points {
+		vertex := mgl32.Vec2{p.vertices[i*2], p.vertices[i*2+1]}
+		if !point.ApproxEqualThreshold(vertex, 1e-4) {
+			t.Errorf("point %d is %v, the vertex drawn is %v", i, point, vertex)
+		}
+	}
+}
+
+func TestSetVertexDataForgetsVertices(t *testing.T) {
+	r := useRecordingGL(t)
+	shader := SharedShaderProgram(VertexShaderBase, "", FragmentShaderSolidColor)
+	p := newPrimitive2D(mgl32.Vec3{}, mgl32.Vec2{1, 1}, shader)
+	p.SetArrayMode(gl.TRIANGLES)
+	p.SetVertices([]float32{0, 0, 100, 0, 0, 100})
+	layout := VertexLayout{{Location: 0, Components: 2, Type: gl.FLOAT}}
+	if err := p.SetVertexData([]float32{0, 0, 2, 0, 0, 2, 2, 2}, layout); err != nil {
+		t.Fatal(err)
+	}
+	if p.ArraySize() != 4 {
+		t.Errorf("ArraySize() = %d, expected 4", p.ArraySize())
+	}
+
+	if err := p.BakeTransform(); err == nil {
+		t.Error("BakeTransform succeeded on the stale vertices")
+	}
+	if attribute := r.attributes[p.vaoId][0]; attribute == nil || attribute.buffer != p.vboData {
+		t.Errorf("attribute 0 is %+v, expected to read from the data buffer %d", attribute, p.vboData)
+	}
+	// Without vertices the box of the primitive size is used
+	if min, max := p.Bounds(); min != (mgl32.Vec2{0, 0}) || max != (mgl32.Vec2{1, 1}) {
+		t.Errorf("Bounds() = %v, %v, expected the box of the size", min, max)
+	}
+}
+
+func TestBakeTransformFailsForVertexData(t *testing.T) {
+	useRecordingGL(t)
+	shader := SharedShaderProgram(VertexShaderBase, "", FragmentShaderSolidColor)
+	layout := VertexLayout{{Location: 0, Components: 2, Type: gl.FLOAT}}
+	tests := []struct {
+		name  string
+		build func(p *Primitive2D) error
+	}{
+		{"vertex data only", func(p *Primitive2D) error {
+			return p.SetVertexData([]float32{0, 0, 1, 0, 0, 1}, layout)
+		}},
+		{"vertex data after SetVertices", func(p *Primitive2D) error {
+			p.SetVertices([]float32{0, 0, 1, 0, 0, 1})
+			return p.SetVertexData([]float32{0, 0, 1, 0, 0, 1}, layout)
+		}},
+	}
+	for _, test := range tests {
+		p := newPrimitive2D(mgl32.Vec3{10, 20, 0}, mgl32.Vec2{1, 1}, shader)
+		if err := test.build(p); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if err := p.BakeTransform(); err == nil {
+			t.Errorf("%s: BakeTransform didn't return an error", test.name)
+		}
+		if p.Position() != (mgl32.Vec3{10, 20, 0}) {
+			t.Errorf("%s: the failed BakeTransform moved the primitive to %v", test.name, p.Position())
+		}
+	}
+}
+
+func TestAppendVerticesKeepsCallerSlice(t *testing.T) {
+	useRecordingGL(t)
+	shader := SharedShaderProgram(VertexShaderBase, "", FragmentShaderSolidColor)
+	p := newPrimitive2D(mgl32.Vec3{}, mgl32.Vec2{1, 1}, shader)
+	buffer := []float32{0, 0, 1, 0, -1, -1}
+	p.SetVertices(buffer[:4])
+
+	p.AppendVertices([]float32{5, 5})
+
+	if buffer[4] != -1 || buffer[5] != -1 {
+		t.Errorf("the spare capacity of the slice passed to SetVertices has been overwritten: %v", buffer)
+	}
+	if p.ArraySize() != 3 || p.vertices[4] != 5 || p.vertices[5] != 5 {
+		t.Errorf("vertices %v, expected the appended point at the end", p.vertices)
+	}
+}
+
+func TestDrawAtInstances(t *testing.T) {
+	r := useRecordingGL(t)
+	shader := SharedShaderProgram(VertexShaderBase, "", FragmentShaderSolidColor)
+	p := newPrimitive2D(mgl32.Vec3{5, 0, 0}, mgl32.Vec2{1, 1}, shader)
+	p.SetArrayMode(gl.TRIANGLES)
+	p.SetVertices([]float32{0, 0, 1, 0, 0, 1})
+	p.SetShadow(mgl32.Vec2{2, 3}, 0, Color{0, 0, 0, 0.5})
+	// Drawn as part of a group
+	group := mgl32.Translate3D(100, 0, 0)
+	p.parentMatrix = &group
+	transforms := []mgl32.Mat4{mgl32.Translate3D(0, 10, 0), mgl32.Translate3D(0, 20, 0)}
+	projection := mgl32.Ident4()
+	r.reset()
+
+	p.DrawAt(&projection, transforms)
+
+	model := group.Mul4(*p.ModelMatrix())
+	shadowOffset := mgl32.Translate3D(2, 3, 0)
+	expected := []mgl32.Mat4{
+		shadowOffset.Mul4(transforms[0]).Mul4(model),
+		shadowOffset.Mul4(transforms[1]).Mul4(model),
+		model,
+		transforms[0].Mul4(model),
+		transforms[1].Mul4(model),
+	}
+	var models []mgl32.Mat4
+	for _, matrix := range r.matrices {
+		if matrix.location == shader.GetUniform("model") {
+			models = append(models, matrix.value)
+		}
+	}
+	if len(models) != len(expected) {
+		t.Fatalf("%d model matrices uploaded, expected %d", len(models), len(expected))
+	}
+	for i := range expected {
+		if !models[i].ApproxEqual(expected[i]) {
+			t.Errorf("model matrix %d is %v, expected %v", i, models[i], expected[i])
+		}
+	}
+	if calls := drawCalls(r); len(calls) != 4 {
+		t.Errorf("draw calls %v, expected a shadow and an instance for each transformation", calls)
+	}
+}
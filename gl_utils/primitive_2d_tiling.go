@@ -0,0 +1,70 @@
+package gl_utils
+
+import (
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// textureTiling how many times the texture repeats across the primitive
+type textureTiling struct {
+	repeat mgl32.Vec2
+	// When set, repeat follows the size of the primitive
+	tileSize mgl32.Vec2
+	bySize   bool
+}
+
+// SetTextureTiling repeats the texture repeatX times horizontally and repeatY times vertically across the primitive,
+// instead of stretching it. The UV coordinates are scaled, so it's meant for coordinates spanning the whole texture
+// (not a region of an atlas). The wrap mode of the current texture is set to gl.REPEAT.
+// Pass 1, 1 to go back to a single copy of the texture
+func (p *Primitive2D) SetTextureTiling(repeatX, repeatY float32) {
+	if repeatX == 1 && repeatY == 1 {
+		p.tiling = nil
+	} else {
+		p.tiling = &textureTiling{repeat: mgl32.Vec2{repeatX, repeatY}}
+		p.setTextureRepeat()
+	}
+	p.uploadUVCoords()
+}
+
+// SetTextureTilingBySize repeats the texture once every tileSize units of the size of the primitive. The number of
+// repetitions is updated when the size changes, e.g. a floor keeps tiles of the same size when stretched (see
+// SetTextureTiling)
+func (p *Primitive2D) SetTextureTilingBySize(tileSize mgl32.Vec2) {
+	p.tiling = &textureTiling{tileSize: tileSize, bySize: true}
+	p.setTextureRepeat()
+	p.updateTilingFromSize()
+}
+
+// TextureTiling returns how many times the texture repeats horizontally and vertically
+func (p *Primitive2D) TextureTiling() (float32, float32) {
+	if p.tiling == nil {
+		return 1, 1
+	}
+	return p.tiling.repeat.X(), p.tiling.repeat.Y()
+}
+
+// updateTilingFromSize recomputes the repetitions of a tiling by size, uploading the UV coordinates again
+func (p *Primitive2D) updateTilingFromSize() {
+	if p.tiling == nil || !p.tiling.bySize || p.tiling.tileSize.X() == 0 || p.tiling.tileSize.Y() == 0 {
+		return
+	}
+	p.tiling.repeat = mgl32.Vec2{p.size.X() / p.tiling.tileSize.X(), p.size.Y() / p.tiling.tileSize.Y()}
+	p.uploadUVCoords()
+}
+
+func (p *Primitive2D) setTextureRepeat() {
+	if p.texture != nil {
+		p.texture.SetWrap(gl.REPEAT, gl.REPEAT)
+	}
+}
+
+// tileUVCoords returns the UV coordinates scaled by the tiling
+func (p *Primitive2D) tileUVCoords(uvCoords []float32) []float32 {
+	tiled := make([]float32, len(uvCoords))
+	for i := 0; i+1 < len(uvCoords); i += 2 {
+		tiled[i] = uvCoords[i] * p.tiling.repeat.X()
+		tiled[i+1] = uvCoords[i+1] * p.tiling.repeat.Y()
+	}
+	return tiled
+}
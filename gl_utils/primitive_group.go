@@ -0,0 +1,111 @@
+package gl_utils
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// PrimitiveGroup a set of primitives moved, rotated and scaled together. The transformation of each primitive is
+// relative to the group
+type PrimitiveGroup struct {
+	primitives  []*Primitive2D
+	position    mgl32.Vec3
+	angle       float32
+	scale       mgl32.Vec2
+	modelMatrix mgl32.Mat4
+	dirty       bool
+}
+
+// NewPrimitiveGroup creates an empty group placed at position
+func NewPrimitiveGroup(position mgl32.Vec3) *PrimitiveGroup {
+	return &PrimitiveGroup{
+		position: position,
+		scale:    mgl32.Vec2{1, 1},
+		dirty:    true,
+	}
+}
+
+// Add adds a primitive to the group
+func (g *PrimitiveGroup) Add(primitive *Primitive2D) {
+	g.primitives = append(g.primitives, primitive)
+}
+
+// Remove removes a primitive from the group, returning false if the primitive wasn't part of it
+func (g *PrimitiveGroup) Remove(primitive *Primitive2D) bool {
+	for i, p := range g.primitives {
+		if p == primitive {
+			g.primitives = append(g.primitives[:i], g.primitives[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Primitives returns the primitives of the group, in drawing order
+func (g *PrimitiveGroup) Primitives() []*Primitive2D {
+	return g.primitives
+}
+
+// Position returns the position of the group
+func (g *PrimitiveGroup) Position() mgl32.Vec3 {
+	return g.position
+}
+
+// SetPosition sets the position of the group
+func (g *PrimitiveGroup) SetPosition(position mgl32.Vec3) {
+	g.position = position
+	g.dirty = true
+}
+
+// Angle returns the rotation of the group, in radians
+func (g *PrimitiveGroup) Angle() float32 {
+	return g.angle
+}
+
+// SetAngle sets the rotation of the group around its position, in radians
+func (g *PrimitiveGroup) SetAngle(radians float32) {
+	g.angle = radians
+	g.dirty = true
+}
+
+// AngleDegrees returns the rotation of the group in degrees
+func (g *PrimitiveGroup) AngleDegrees() float32 {
+	return mgl32.RadToDeg(g.angle)
+}
+
+// SetAngleDegrees sets the rotation of the group around its position, in degrees
+func (g *PrimitiveGroup) SetAngleDegrees(degrees float32) {
+	g.SetAngle(mgl32.DegToRad(degrees))
+}
+
+// Scale returns the scaling factor of the group
+func (g *PrimitiveGroup) Scale() mgl32.Vec2 {
+	return g.scale
+}
+
+// SetScale sets the scaling factor of the group
+func (g *PrimitiveGroup) SetScale(scale mgl32.Vec2) {
+	g.scale = scale
+	g.dirty = true
+}
+
+// ModelMatrix returns the transformation of the group
+func (g *PrimitiveGroup) ModelMatrix() *mgl32.Mat4 {
+	if g.dirty {
+		translation := mgl32.Translate3D(g.position.X(), g.position.Y(), g.position.Z())
+		rotation := mgl32.HomogRotate3DZ(g.angle)
+		scale := mgl32.Scale3D(g.scale.X(), g.scale.Y(), 1)
+		g.modelMatrix = translation.Mul4(rotation).Mul4(scale)
+		g.dirty = false
+	}
+	return &g.modelMatrix
+}
+
+// Draw draws all the primitives of the group, applying the group transformation on top of their own
+func (g *PrimitiveGroup) Draw(projectionMatrix *mgl32.Mat4) {
+	groupMatrix := g.ModelMatrix()
+	for _, p := range g.primitives {
+		p.parentMatrix = groupMatrix
+		p.Draw(projectionMatrix)
+		p.parentMatrix = nil
+	}
+}
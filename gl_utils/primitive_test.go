@@ -0,0 +1,37 @@
+package gl_utils
+
+import (
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestSetMaterialNilRestoresShader(t *testing.T) {
+	useRecordingGL(t)
+	quad := NewQuadPrimitive(mgl32.Vec3{}, mgl32.Vec2{10, 10})
+	shader := quad.Shader()
+	first := NewMaterial(SharedShaderProgram(VertexShaderBase, "", FragmentShaderCircle))
+	second := NewMaterial(SharedShaderProgram(VertexShaderBase, "", FragmentShaderRoundedRect))
+
+	quad.SetMaterial(first)
+	if quad.Shader() != first.Shader() {
+		t.Error("the material didn't replace the shader")
+	}
+	quad.SetMaterial(second)
+	if quad.Shader() != second.Shader() {
+		t.Error("the second material didn't replace the shader")
+	}
+	quad.SetMaterial(nil)
+	if quad.Shader() != shader {
+		t.Error("removing the material didn't restore the shader of the primitive")
+	}
+	if quad.Material() != nil {
+		t.Error("the material hasn't been removed")
+	}
+
+	// Removing a material that isn't set leaves the shader alone
+	quad.SetMaterial(nil)
+	if quad.Shader() != shader {
+		t.Error("the shader changed")
+	}
+}
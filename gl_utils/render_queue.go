@@ -0,0 +1,255 @@
+package gl_utils
+
+import (
+	"sort"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// RenderQueue collects the primitives to draw in a frame and draws them grouped by render state (see CanBatchWith).
+// Within a group the primitives are drawn by increasing Z, and consecutive filled primitives (TRIANGLES and
+// TRIANGLE_FAN, like quads and text) with the same color and depth are merged into a single draw call. Primitives
+// with a material, a custom vertex shader, lights or per-vertex data besides the UVs are drawn one by one.
+// The merging is done by the queue itself on the CPU, there is no lower level sprite batch to build on.
+// The groups are reordered, so primitives overlapping without depth testing may be drawn in a different order than
+// submitted: use a DrawList when the order matters
+type RenderQueue struct {
+	items []renderQueueItem
+	// Materials numbered in order of submission, 0 stands for no material
+	materials map[*Material]int
+	// Primitive drawing the merged geometry, created by the first merge
+	batch *Primitive2D
+	// Scratch slices the geometry is merged into, the batch keeps referencing them after the upload: they are
+	// overwritten by the next merge, which uploads the batch again
+	vertices []float32
+	uvCoords []float32
+}
+
+type renderQueueItem struct {
+	primitive *Primitive2D
+	key       renderQueueKey
+	index     int
+}
+
+// renderQueueKey the state a primitive is drawn with. Primitives with the same key are drawn one after the other
+type renderQueueKey struct {
+	blendMode BlendMode
+	shader    uint32
+	texture   uint32
+	arrayMode uint32
+	material  int
+}
+
+// NewRenderQueue creates an empty render queue
+func NewRenderQueue() *RenderQueue {
+	return &RenderQueue{materials: make(map[*Material]int)}
+}
+
+// Submit adds a primitive to the queue, it will be drawn by the next Render
+func (q *RenderQueue) Submit(primitive *Primitive2D) {
+	key := primitive.renderQueueKey()
+	if primitive.material != nil {
+		if _, found := q.materials[primitive.material]; !found {
+			q.materials[primitive.material] = len(q.materials) + 1
+		}
+		key.material = q.materials[primitive.material]
+	}
+	q.items = append(q.items, renderQueueItem{
+		primitive: primitive,
+		key:       key,
+		index:     len(q.items),
+	})
+}
+
+// Len returns the number of primitives waiting to be drawn
+func (q *RenderQueue) Len() int {
+	return len(q.items)
+}
+
+// Clear removes all the primitives from the queue without drawing them
+func (q *RenderQueue) Clear() {
+	q.items = q.items[:0]
+	for material := range q.materials {
+		delete(q.materials, material)
+	}
+}
+
+// Render draws the submitted primitives group by group and empties the queue
+func (q *RenderQueue) Render(projectionMatrix *mgl32.Mat4) {
+	sort.Slice(q.items, func(i, j int) bool {
+		a, b := q.items[i], q.items[j]
+		if a.key != b.key {
+			return a.key.less(b.key)
+		}
+		if za, zb := a.primitive.position.Z(), b.primitive.position.Z(); za != zb {
+			return za < zb
+		}
+		// Keep the submission order of primitives at the same depth
+		return a.index < b.index
+	})
+	for start := 0; start < len(q.items); {
+		end := start + 1
+		first := q.items[start]
+		if first.primitive.mergeable() {
+			for end < len(q.items) && q.items[end].key == first.key && q.items[end].primitive.mergeable() &&
+				q.items[end].primitive.canMergeWith(first.primitive) {
+				end++
+			}
+		}
+		if end-start == 1 {
+			first.primitive.Draw(projectionMatrix)
+		} else {
+			q.drawMerged(q.items[start:end], projectionMatrix)
+		}
+		start = end
+	}
+	q.Clear()
+}
+
+// Release deletes the buffers used to draw the merged primitives
+func (q *RenderQueue) Release() {
+	if q.batch != nil {
+		q.batch.Release()
+		q.batch = nil
+	}
+}
+
+// drawMerged draws the primitives with a single draw call, their vertices are transformed on the CPU and uploaded in
+// a shared buffer. The batch is uploaded from the scratch slices every time, so it's never drawn with stale data
+func (q *RenderQueue) drawMerged(items []renderQueueItem, projectionMatrix *mgl32.Mat4) {
+	q.vertices = q.vertices[:0]
+	q.uvCoords = q.uvCoords[:0]
+	for _, item := range items {
+		q.vertices, q.uvCoords = item.primitive.appendTriangles(q.vertices, q.uvCoords)
+	}
+
+	first := items[0].primitive
+	if q.batch == nil {
+		q.batch = newPrimitive2D(mgl32.Vec3{}, mgl32.Vec2{1, 1}, first.shaderProgram)
+		q.batch.arrayMode = gl.TRIANGLES
+	}
+	b := q.batch
+	b.shaderProgram = first.shaderProgram
+	b.texture = first.texture
+	b.blendMode = first.blendMode
+	b.color = *first.drawColor()
+	b.cullMode = first.cullMode
+	b.frontFaceCW = first.frontFaceCW
+	b.depth = first.depth
+	b.SetPosition(mgl32.Vec3{0, 0, first.position.Z()})
+	b.SetVertices(q.vertices)
+	b.SetUVCoords(q.uvCoords)
+	b.Draw(projectionMatrix)
+}
+
+// mergeable returns whether RenderQueue can merge the geometry of the primitive with the one of other primitives:
+// the primitive is filled, drawn with the base vertex shader and has no state that needs its own draw call
+func (p *Primitive2D) mergeable() bool {
+	if p.arrayMode != gl.TRIANGLES && p.arrayMode != gl.TRIANGLE_FAN {
+		return false
+	}
+	if p.arraySize == 0 || p.Ready() != nil || p.material != nil || len(p.textures) > 0 {
+		return false
+	}
+	if p.vboColors != 0 || p.vboUVCoords2 != 0 || p.vboLayers != 0 || p.vboData != 0 {
+		return false
+	}
+	if p.outline != nil || p.shadow != nil || p.uvTransform != nil || p.modelOverride != nil {
+		return false
+	}
+	if len(p.vertices) < int(p.arraySize)*2 || (p.uvCoords != nil && len(p.uvCoords) < int(p.arraySize)*2) {
+		return false
+	}
+	sources := p.shaderProgram.cacheKey
+	return sources.vertex == VertexShaderBase && sources.geometry == "" && p.shaderProgram.GetUniform("num_lights") < 0
+}
+
+// canMergeWith returns whether the primitive has the same uniforms and state of other, besides the model matrix
+func (p *Primitive2D) canMergeWith(other *Primitive2D) bool {
+	return *p.drawColor() == *other.drawColor() &&
+		p.position.Z() == other.position.Z() &&
+		p.cullMode == other.cullMode &&
+		p.frontFaceCW == other.frontFaceCW &&
+		p.depth == other.depth &&
+		(p.uvCoords == nil) == (other.uvCoords == nil)
+}
+
+// appendTriangles appends the vertices of the primitive as independent triangles, transformed by its model matrix,
+// and their UV coordinates (0, 0 if the primitive has none). The triangles of a mirrored primitive are rewound, so
+// they face the same side as when the primitive is drawn on its own
+func (p *Primitive2D) appendTriangles(vertices []float32, uvCoords []float32) ([]float32, []float32) {
+	model := p.drawModelMatrix()
+	var primitiveUVs []float32
+	if p.uvCoords != nil {
+		primitiveUVs = p.drawUVCoords()
+	}
+	appendVertex := func(i int32) {
+		vertex := model.Mul4x1(mgl32.Vec4{p.vertices[i*2], p.vertices[i*2+1], 0, 1})
+		vertices = append(vertices, vertex.X(), vertex.Y())
+		if primitiveUVs != nil {
+			uvCoords = append(uvCoords, primitiveUVs[i*2], primitiveUVs[i*2+1])
+		} else {
+			uvCoords = append(uvCoords, 0, 0)
+		}
+	}
+	appendTriangle := func(a, b, c int32) {
+		if p.mirrored() {
+			b, c = c, b
+		}
+		appendVertex(a)
+		appendVertex(b)
+		appendVertex(c)
+	}
+
+	if p.arrayMode == gl.TRIANGLE_FAN {
+		for i := int32(1); i+1 < p.arraySize; i++ {
+			appendTriangle(0, i, i+1)
+		}
+	} else {
+		for i := int32(0); i+2 < p.arraySize; i += 3 {
+			appendTriangle(i, i+1, i+2)
+		}
+	}
+	return vertices, uvCoords
+}
+
+// CanBatchWith returns whether the primitive is drawn with the same render state of other: shader program, texture,
+// blend mode, array mode and material. Primitives that can be batched together can be drawn one after the other
+// without changing the GL state (RenderQueue relies on it)
+func (p *Primitive2D) CanBatchWith(other *Primitive2D) bool {
+	return p.renderQueueKey() == other.renderQueueKey() && p.material == other.material
+}
+
+// renderQueueKey returns the state the primitive is drawn with, except the material
+func (p *Primitive2D) renderQueueKey() renderQueueKey {
+	key := renderQueueKey{
+		blendMode: p.blendMode,
+		arrayMode: p.arrayMode,
+	}
+	if p.shaderProgram != nil {
+		key.shader = p.shaderProgram.ID()
+	}
+	if p.texture != nil && p.material == nil {
+		key.texture = p.texture.ID()
+	}
+	return key
+}
+
+// less orders the keys by blend mode, then by shader and texture, which are the most expensive changes of state, then
+// by array mode and material
+func (k renderQueueKey) less(other renderQueueKey) bool {
+	if k.blendMode != other.blendMode {
+		return k.blendMode < other.blendMode
+	}
+	if k.shader != other.shader {
+		return k.shader < other.shader
+	}
+	if k.texture != other.texture {
+		return k.texture < other.texture
+	}
+	if k.arrayMode != other.arrayMode {
+		return k.arrayMode < other.arrayMode
+	}
+	return k.material < other.material
+}
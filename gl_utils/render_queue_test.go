@@ -0,0 +1,104 @@
+package gl_utils
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// drawCalls returns the DrawArrays calls recorded
+func drawCalls(r *recordingGLContext) []string {
+	calls := make([]string, 0)
+	for _, call := range r.calls {
+		if strings.HasPrefix(call, "DrawArrays(") {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// signedArea returns the doubled signed area of the triangle starting at the vertex index i
+func signedArea(vertices []float32, i int) float32 {
+	ax, ay := vertices[i*2], vertices[i*2+1]
+	bx, by := vertices[i*2+2], vertices[i*2+3]
+	cx, cy := vertices[i*2+4], vertices[i*2+5]
+	return (bx-ax)*(cy-ay) - (by-ay)*(cx-ax)
+}
+
+func TestRenderQueueMergesQuads(t *testing.T) {
+	r := useRecordingGL(t)
+	texture, err := NewTextureFromPixels(2, 2, gl.RGBA, make([]uint8, 16))
+	if err != nil {
+		t.Fatal(err)
+	}
+	queue := NewRenderQueue()
+	positions := []mgl32.Vec3{{10, 20, 0}, {50, 20, 0}, {90, 20, 0}}
+	for i, position := range positions {
+		quad := NewQuadPrimitive(position, mgl32.Vec2{8, 4})
+		quad.texture = texture
+		// A mirrored quad can be merged too
+		quad.SetFlipX(i == 1)
+		queue.Submit(quad)
+	}
+	projection := mgl32.Ortho2D(0, 100, 100, 0)
+	r.reset()
+
+	queue.Render(&projection)
+
+	calls := drawCalls(r)
+	expected := fmt.Sprintf("DrawArrays(%d, 0, 18)", gl.TRIANGLES)
+	if len(calls) != 1 || calls[0] != expected {
+		t.Fatalf("draw calls %v, expected [%s]", calls, expected)
+	}
+	vertices := queue.batch.vertices
+	if len(vertices) != 18*2 {
+		t.Fatalf("%d vertex coordinates merged, expected %d", len(vertices), 18*2)
+	}
+	for i, position := range positions {
+		// The first triangle of the fan starts at the corner on the origin of the quad
+		x, y := vertices[i*12], vertices[i*12+1]
+		if x != position.X() || y != position.Y() {
+			t.Errorf("quad %d starts at %v,%v, expected %v,%v", i, x, y, position.X(), position.Y())
+		}
+	}
+	for i := 0; i < 6; i++ {
+		if signedArea(vertices, i*3)*signedArea(vertices, 0) <= 0 {
+			t.Errorf("triangle %d is wound differently from the first one", i)
+		}
+	}
+	if queue.Len() != 0 {
+		t.Errorf("%d primitives left in the queue", queue.Len())
+	}
+}
+
+func TestRenderQueueDrawsIncompatiblePrimitivesApart(t *testing.T) {
+	r := useRecordingGL(t)
+	queue := NewRenderQueue()
+	red := NewRectPrimitive(mgl32.Vec3{}, mgl32.Vec2{5, 5}, true)
+	red.SetColor(Color{1, 0, 0, 1})
+	otherRed := NewRectPrimitive(mgl32.Vec3{10, 0, 0}, mgl32.Vec2{5, 5}, true)
+	otherRed.SetColor(Color{1, 0, 0, 1})
+	blue := NewRectPrimitive(mgl32.Vec3{20, 0, 0}, mgl32.Vec2{5, 5}, true)
+	blue.SetColor(Color{0, 0, 1, 1})
+	outline := NewRectPrimitive(mgl32.Vec3{30, 0, 0}, mgl32.Vec2{5, 5}, false)
+	for _, p := range []*Primitive2D{red, otherRed, blue, outline} {
+		queue.Submit(p)
+	}
+	projection := mgl32.Ortho2D(0, 100, 100, 0)
+	r.reset()
+
+	queue.Render(&projection)
+
+	calls := drawCalls(r)
+	expected := []string{
+		fmt.Sprintf("DrawArrays(%d, 0, 4)", gl.LINE_LOOP),
+		fmt.Sprintf("DrawArrays(%d, 0, 12)", gl.TRIANGLES),
+		fmt.Sprintf("DrawArrays(%d, 0, 4)", gl.TRIANGLE_FAN),
+	}
+	if strings.Join(calls, " ") != strings.Join(expected, " ") {
+		t.Errorf("draw calls %v, expected %v", calls, expected)
+	}
+}
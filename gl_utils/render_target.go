@@ -0,0 +1,116 @@
+package gl_utils
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// RenderTarget an offscreen framebuffer rendering into a texture, with its own depth buffer
+type RenderTarget struct {
+	fboId            uint32
+	depthBufferId    uint32
+	texture          *Texture
+	previousViewport [4]int32
+	previousFbo      int32
+}
+
+// NewRenderTarget creates a render target of width x height pixels
+func NewRenderTarget(width int, height int) (*RenderTarget, error) {
+	if width <= 0 || height <= 0 {
+		return nil, fmt.Errorf("invalid render target size %dx%d", width, height)
+	}
+	texture, err := NewEmptyTexture(width, height, gl.RGBA)
+	if err != nil {
+		return nil, err
+	}
+	r := &RenderTarget{texture: texture}
+
+	glc.GenFramebuffers(1, &r.fboId)
+	glc.BindFramebuffer(gl.FRAMEBUFFER, r.fboId)
+	glc.FramebufferTexture2D(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT0, gl.TEXTURE_2D, texture.id, 0)
+
+	glc.GenRenderbuffers(1, &r.depthBufferId)
+	glc.BindRenderbuffer(gl.RENDERBUFFER, r.depthBufferId)
+	glc.RenderbufferStorage(gl.RENDERBUFFER, gl.DEPTH_COMPONENT24, int32(width), int32(height))
+	glc.FramebufferRenderbuffer(gl.FRAMEBUFFER, gl.DEPTH_ATTACHMENT, gl.RENDERBUFFER, r.depthBufferId)
+	glc.BindRenderbuffer(gl.RENDERBUFFER, 0)
+
+	status := glc.CheckFramebufferStatus(gl.FRAMEBUFFER)
+	glc.BindFramebuffer(gl.FRAMEBUFFER, 0)
+	if status != gl.FRAMEBUFFER_COMPLETE {
+		r.Release()
+		return nil, fmt.Errorf("the framebuffer is incomplete (status 0x%x)", status)
+	}
+	return r, nil
+}
+
+// Bind redirects the drawing into the render target, setting the viewport to its size. The previous framebuffer
+// and viewport are restored by Unbind
+func (r *RenderTarget) Bind() {
+	glc.GetIntegerv(gl.FRAMEBUFFER_BINDING, &r.previousFbo)
+	glc.GetIntegerv(gl.VIEWPORT, &r.previousViewport[0])
+	glc.BindFramebuffer(gl.FRAMEBUFFER, r.fboId)
+	glc.Viewport(0, 0, r.texture.width, r.texture.height)
+}
+
+// Unbind restores the framebuffer and the viewport active before Bind
+func (r *RenderTarget) Unbind() {
+	glc.BindFramebuffer(gl.FRAMEBUFFER, uint32(r.previousFbo))
+	glc.Viewport(r.previousViewport[0], r.previousViewport[1], r.previousViewport[2], r.previousViewport[3])
+}
+
+// Texture returns the texture the render target draws into
+func (r *RenderTarget) Texture() *Texture {
+	return r.texture
+}
+
+// Width returns the width of the render target in pixels
+func (r *RenderTarget) Width() int {
+	return int(r.texture.width)
+}
+
+// Height returns the height of the render target in pixels
+func (r *RenderTarget) Height() int {
+	return int(r.texture.height)
+}
+
+// ReadPixel returns the color of a pixel of the render target. The coordinates have the origin in the top-left
+// corner, like the screen coordinates
+func (r *RenderTarget) ReadPixel(x int, y int) (Color, error) {
+	var previousFbo int32
+	glc.GetIntegerv(gl.READ_FRAMEBUFFER_BINDING, &previousFbo)
+	glc.BindFramebuffer(gl.READ_FRAMEBUFFER, r.fboId)
+	color, err := ReadFramebufferPixel(x, y, r.Width(), r.Height())
+	glc.BindFramebuffer(gl.READ_FRAMEBUFFER, uint32(previousFbo))
+	return color, err
+}
+
+// Release deletes the framebuffer, its depth buffer and its texture
+func (r *RenderTarget) Release() {
+	glc.DeleteFramebuffers(1, &r.fboId)
+	glc.DeleteRenderbuffers(1, &r.depthBufferId)
+	r.texture.Release()
+}
+
+// ReadFramebufferPixel returns the color of a pixel of the framebuffer currently bound for reading (e.g. the window),
+// whose size is width x height pixels. The coordinates have the origin in the top-left corner, like the screen
+// coordinates. The OpenGL error state isn't checked, so errors raised before the call are left for CheckGLError
+func ReadFramebufferPixel(x int, y int, width int, height int) (Color, error) {
+	if x < 0 || y < 0 || x >= width || y >= height {
+		return Color{}, errors.New("the pixel is outside of the framebuffer")
+	}
+	if status := glc.CheckFramebufferStatus(gl.READ_FRAMEBUFFER); status != gl.FRAMEBUFFER_COMPLETE {
+		return Color{}, fmt.Errorf("the framebuffer can't be read, status 0x%x", status)
+	}
+	var pixel [4]uint8
+	// OpenGL has the origin of the framebuffer in the bottom-left corner
+	glc.ReadPixels(int32(x), int32(height-1-y), 1, 1, gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(&pixel[0]))
+	return Color{
+		float32(pixel[0]) / 255,
+		float32(pixel[1]) / 255,
+		float32(pixel[2]) / 255,
+		float32(pixel[3]) / 255,
+	}, nil
+}
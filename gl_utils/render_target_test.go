@@ -0,0 +1,69 @@
+package gl_utils
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// indexOf returns the position of the first call recorded equal to the one passed, -1 if it wasn't made
+func indexOf(r *recordingGLContext, call string) int {
+	for i, recorded := range r.calls {
+		if recorded == call {
+			return i
+		}
+	}
+	return -1
+}
+
+func TestDrawToRestoresFramebufferAndViewport(t *testing.T) {
+	r := useRecordingGL(t)
+	glc.Viewport(0, 0, 800, 600)
+	target, err := NewRenderTarget(64, 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := NewRectPrimitive(mgl32.Vec3{}, mgl32.Vec2{5, 5}, true)
+	projection := mgl32.Ident4()
+	r.reset()
+
+	p.DrawTo(target, &projection)
+
+	bind := indexOf(r, fmt.Sprintf("BindFramebuffer(%d, %d)", gl.FRAMEBUFFER, target.fboId))
+	draw := indexOf(r, fmt.Sprintf("DrawArrays(%d, 0, 4)", gl.TRIANGLE_FAN))
+	unbind := indexOf(r, fmt.Sprintf("BindFramebuffer(%d, 0)", gl.FRAMEBUFFER))
+	if bind < 0 || draw < bind || unbind < draw {
+		t.Errorf("the primitive isn't drawn while the render target is bound: %v", r.calls)
+	}
+	if indexOf(r, "Viewport(0, 0, 64, 32)") < 0 {
+		t.Error("the viewport hasn't been set to the size of the render target")
+	}
+	if r.boundFramebuffer != 0 || r.viewport != [4]int32{0, 0, 800, 600} {
+		t.Errorf("framebuffer %d and viewport %v after the draw, expected 0 and the window", r.boundFramebuffer, r.viewport)
+	}
+}
+
+func TestReadPixelOutsideRenderTarget(t *testing.T) {
+	r := useRecordingGL(t)
+	target, err := NewRenderTarget(4, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.reset()
+
+	if _, err := target.ReadPixel(4, 0); err == nil {
+		t.Error("a pixel outside of the render target has been read")
+	}
+	if _, err := target.ReadPixel(1, 0); err != nil {
+		t.Fatal(err)
+	}
+	// The origin of the framebuffer is in the bottom-left corner
+	if indexOf(r, fmt.Sprintf("ReadPixels(1, 3, 1, 1, %d, %d)", gl.RGBA, gl.UNSIGNED_BYTE)) < 0 {
+		t.Errorf("the pixel hasn't been read from the bottom row: %v", r.calls)
+	}
+	if r.boundFramebuffer != 0 {
+		t.Errorf("the framebuffer %d is still bound for reading", r.boundFramebuffer)
+	}
+}
@@ -0,0 +1,62 @@
+package gl_utils
+
+import (
+	"math"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// ScrollingBackground a textured quad whose texture scrolls endlessly. The texture is shifted by the shader, the
+// geometry is never uploaded again
+type ScrollingBackground struct {
+	Primitive2D
+	scroll mgl32.Vec2
+}
+
+// NewScrollingBackground creates a quad filled with the texture, which is set to repeat on both axes
+func NewScrollingBackground(position mgl32.Vec3, size mgl32.Vec2, texture *Texture) *ScrollingBackground {
+	b := &ScrollingBackground{
+		Primitive2D: *NewQuadPrimitive(position, size),
+	}
+	texture.SetWrap(gl.REPEAT, gl.REPEAT)
+	b.texture = texture
+
+	material := NewMaterial(SharedShaderProgram(VertexShaderBase, "", FragmentShaderScrolling))
+	material.SetTexture("tex", texture)
+	material.SetUniform("uv_offset", mgl32.Vec2{})
+	b.SetMaterial(material)
+	return b
+}
+
+// Scroll returns the current offset of the texture, in UV units
+func (b *ScrollingBackground) Scroll() mgl32.Vec2 {
+	return b.scroll
+}
+
+// SetScroll sets the offset of the texture, in UV units: an offset of 1 shifts the texture by its whole size
+func (b *ScrollingBackground) SetScroll(offsetU float32, offsetV float32) {
+	b.scroll = mgl32.Vec2{offsetU, offsetV}
+	// Only the fractional part matters with a repeating texture, dropping the rest keeps the precision in the shader
+	b.material.SetUniform("uv_offset", mgl32.Vec2{
+		offsetU - float32(math.Floor(float64(offsetU))),
+		offsetV - float32(math.Floor(float64(offsetV))),
+	})
+}
+
+const (
+	// FragmentShaderScrolling samples the texture shifted by the 'uv_offset' uniform
+	FragmentShaderScrolling = `
+        #version 410 core
+
+        in vec2 uv_out;
+        out vec4 color;
+
+        uniform sampler2D tex;
+        uniform vec2 uv_offset;
+
+        void main() {
+            color = texture(tex, uv_out + uv_offset);
+        }
+        ` + "\x00"
+)
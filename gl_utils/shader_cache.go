@@ -0,0 +1,32 @@
+package gl_utils
+
+// shaderSources identifies a shader program by the source code of its shaders
+type shaderSources struct {
+	vertex   string
+	geometry string
+	fragment string
+}
+
+// Programs compiled by SharedShaderProgram, indexed by their source code
+var shaderCache = make(map[shaderSources]*ShaderProgram)
+
+// SharedShaderProgram returns a program built from the shaders source code passed. Programs with identical sources
+// are compiled only once and shared. The programs are owned by the cache, Release does nothing on them and they are
+// dropped only by ClearShaderCache
+func SharedShaderProgram(vertSource string, geomSource string, fragSource string) *ShaderProgram {
+	key := shaderSources{vertSource, geomSource, fragSource}
+	if s, found := shaderCache[key]; found {
+		return s
+	}
+	s := NewShaderProgram(vertSource, geomSource, fragSource)
+	s.shared = true
+	shaderCache[key] = s
+	s.cacheKey = key
+	return s
+}
+
+// ClearShaderCache forgets the programs compiled so far, the following calls to SharedShaderProgram compile them
+// again (e.g. after the shaders have been modified). The programs aren't deleted, since primitives may still use them
+func ClearShaderCache() {
+	shaderCache = make(map[shaderSources]*ShaderProgram)
+}
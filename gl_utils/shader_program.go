@@ -1,8 +1,10 @@
 package gl_utils
 
 import (
+	"errors"
 	"fmt"
 	"github.com/go-gl/mathgl/mgl64"
+	"sort"
 	"strings"
 
 	"github.com/go-gl/gl/v4.1-core/gl"
@@ -23,12 +25,15 @@ const (
 type ShaderProgram struct {
 	id       uint32
 	uniforms map[string]int32
+	// Set for the programs returned by SharedShaderProgram
+	shared   bool
+	cacheKey shaderSources
 }
 
 // NewDefaultShaderProgram creates a base shader that can render solid color pixels
 func NewDefaultShaderProgram() *ShaderProgram {
 	s := ShaderProgram{}
-	s.id = gl.CreateProgram()
+	s.id = glc.CreateProgram()
 
 	s.AttachShader(VertexShaderBase, VERTEX)
 	s.AttachShader(FragmentShaderSolidColor, FRAGMENT)
@@ -40,7 +45,7 @@ func NewDefaultShaderProgram() *ShaderProgram {
 // NewShaderProgram creates a new program using the shaders source code passed as plain text
 func NewShaderProgram(vertSource string, geomSource string, fragSource string) *ShaderProgram {
 	s := ShaderProgram{}
-	s.id = gl.CreateProgram()
+	s.id = glc.CreateProgram()
 
 	if vertSource != "" {
 		s.AttachShader(vertSource, VERTEX)
@@ -56,10 +61,63 @@ func NewShaderProgram(vertSource string, geomSource string, fragSource string) *
 	return &s
 }
 
-// Release releases all the resources associated with this program
+// NewShaderProgramWithDefines creates a new program like NewShaderProgram, adding a '#define KEY VALUE' line for
+// each define to every shader. This allows variations of the same shader source
+func NewShaderProgramWithDefines(vertSource string, geomSource string, fragSource string, defines map[string]string) *ShaderProgram {
+	return NewShaderProgram(
+		injectDefines(vertSource, defines),
+		injectDefines(geomSource, defines),
+		injectDefines(fragSource, defines),
+	)
+}
+
+// SharedShaderProgramWithDefines is like NewShaderProgramWithDefines, but the program is shared like with
+// SharedShaderProgram. Each set of defines produces a distinct program
+func SharedShaderProgramWithDefines(vertSource string, geomSource string, fragSource string, defines map[string]string) *ShaderProgram {
+	return SharedShaderProgram(
+		injectDefines(vertSource, defines),
+		injectDefines(geomSource, defines),
+		injectDefines(fragSource, defines),
+	)
+}
+
+// injectDefines adds the defines to the shader source, right after the '#version' directive which has to be the
+// first one in GLSL. The defines are sorted by name, so the same set always produces the same source
+func injectDefines(source string, defines map[string]string) string {
+	if source == "" || len(defines) == 0 {
+		return source
+	}
+	names := make([]string, 0, len(defines))
+	for name := range defines {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var lines strings.Builder
+	for _, name := range names {
+		lines.WriteString(fmt.Sprintf("#define %s %s\n", name, defines[name]))
+	}
+
+	insertAt := 0
+	if version := strings.Index(source, "#version"); version >= 0 {
+		endOfLine := strings.IndexByte(source[version:], '\n')
+		if endOfLine < 0 {
+			// Only the version directive, the defines go on a new line
+			return source + "\n" + lines.String()
+		}
+		insertAt = version + endOfLine + 1
+	}
+	return source[:insertAt] + lines.String() + source[insertAt:]
+}
+
+// Release releases all the resources associated with this program. It does nothing on shared programs (see
+// SharedShaderProgram), which are owned by the cache
 func (s *ShaderProgram) Release() {
 	if s.id == 0 {
 		fmt.Printf("Error: Trying to release a non initialized shader program")
+		return
+	}
+	if s.shared {
+		return
 	}
 	// TODO
 	//var shadersId [8]uint32
@@ -68,47 +126,199 @@ func (s *ShaderProgram) Release() {
 	//	gl.DetachShader(self._program_id, shader_id)
 	//	gl.DeleteShader(shader_id)
 
-	gl.DeleteProgram(s.id)
+	glc.DeleteProgram(s.id)
+	s.id = 0
 }
 
 // AttachShader attaches a shader to this program
 func (s *ShaderProgram) AttachShader(source string, shaderType ShaderType) {
-	shaderID := gl.CreateShader(uint32(shaderType))
+	shaderID := glc.CreateShader(uint32(shaderType))
 	cSource, free := gl.Strs(source)
-	gl.ShaderSource(shaderID, 1, cSource, nil)
+	glc.ShaderSource(shaderID, 1, cSource, nil)
 	free()
-	gl.CompileShader(shaderID)
+	glc.CompileShader(shaderID)
 
 	var status int32
-	gl.GetShaderiv(shaderID, gl.COMPILE_STATUS, &status)
+	glc.GetShaderiv(shaderID, gl.COMPILE_STATUS, &status)
 	if status == gl.FALSE {
 		var logLength int32
-		gl.GetShaderiv(shaderID, gl.INFO_LOG_LENGTH, &logLength)
+		glc.GetShaderiv(shaderID, gl.INFO_LOG_LENGTH, &logLength)
 
 		logStr := strings.Repeat("\x00", int(logLength+1))
-		gl.GetShaderInfoLog(shaderID, logLength, nil, gl.Str(logStr))
+		glc.GetShaderInfoLog(shaderID, logLength, nil, gl.Str(logStr))
 
 		fmt.Printf("Error: failed to compile %v: %v", source, logStr)
 	}
-	gl.AttachShader(s.id, shaderID)
+	glc.AttachShader(s.id, shaderID)
 }
 
 // Link links together all the shaders into a shader program
 func (s *ShaderProgram) Link() {
-	gl.LinkProgram(s.id)
+	glc.LinkProgram(s.id)
 	var status int32
-	gl.GetProgramiv(s.id, gl.LINK_STATUS, &status)
+	glc.GetProgramiv(s.id, gl.LINK_STATUS, &status)
 	if status == gl.FALSE {
 		var logLength int32
-		gl.GetProgramiv(s.id, gl.INFO_LOG_LENGTH, &logLength)
+		glc.GetProgramiv(s.id, gl.INFO_LOG_LENGTH, &logLength)
 
 		logStr := strings.Repeat("\x00", int(logLength+1))
-		gl.GetProgramInfoLog(s.id, logLength, nil, gl.Str(logStr))
+		glc.GetProgramInfoLog(s.id, logLength, nil, gl.Str(logStr))
 
 		fmt.Printf("Error: failed to link program: %v", logStr)
 	}
 }
 
+// Validate checks whether the program can be executed in the current OpenGL state
+func (s *ShaderProgram) Validate() error {
+	glc.ValidateProgram(s.id)
+	var status int32
+	glc.GetProgramiv(s.id, gl.VALIDATE_STATUS, &status)
+	if status == gl.FALSE {
+		var logLength int32
+		glc.GetProgramiv(s.id, gl.INFO_LOG_LENGTH, &logLength)
+
+		logStr := strings.Repeat("\x00", int(logLength+1))
+		glc.GetProgramInfoLog(s.id, logLength, nil, gl.Str(logStr))
+
+		return errors.New("failed to validate program: " + strings.TrimRight(logStr, "\x00"))
+	}
+	return nil
+}
+
+// AttributeLocation returns the location of a vertex attribute, -1 if the attribute is not active
+func (s *ShaderProgram) AttributeLocation(name string) int32 {
+	return glc.GetAttribLocation(s.id, gl.Str(name+"\x00"))
+}
+
+// ActiveAttributes returns the names of the vertex attributes used by the program
+func (s *ShaderProgram) ActiveAttributes() []string {
+	var numAttributes, maxLength int32
+	glc.GetProgramiv(s.id, gl.ACTIVE_ATTRIBUTES, &numAttributes)
+	glc.GetProgramiv(s.id, gl.ACTIVE_ATTRIBUTE_MAX_LENGTH, &maxLength)
+
+	names := make([]string, 0, numAttributes)
+	buffer := make([]uint8, maxLength+1)
+	for i := int32(0); i < numAttributes; i++ {
+		var length, size int32
+		var attributeType uint32
+		glc.GetActiveAttrib(s.id, uint32(i), maxLength+1, &length, &size, &attributeType, &buffer[0])
+		names = append(names, string(buffer[:length]))
+	}
+	return names
+}
+
+// DumpUniforms returns the current values of the active uniforms of the program, formatted as text. Meant for
+// debugging: it queries OpenGL for every uniform
+func (s *ShaderProgram) DumpUniforms() map[string]string {
+	var numUniforms, maxLength int32
+	glc.GetProgramiv(s.id, gl.ACTIVE_UNIFORMS, &numUniforms)
+	glc.GetProgramiv(s.id, gl.ACTIVE_UNIFORM_MAX_LENGTH, &maxLength)
+
+	values := make(map[string]string, numUniforms)
+	buffer := make([]uint8, maxLength+1)
+	for i := int32(0); i < numUniforms; i++ {
+		var length, size int32
+		var uniformType uint32
+		glc.GetActiveUniform(s.id, uint32(i), maxLength+1, &length, &size, &uniformType, &buffer[0])
+		name := string(buffer[:length])
+		if size == 1 {
+			values[name] = s.uniformValue(s.GetUniform(name), uniformType)
+			continue
+		}
+		// Array uniforms are reported as 'name[0]', each element has its own location
+		baseName := strings.TrimSuffix(name, "[0]")
+		for element := int32(0); element < size; element++ {
+			elementName := fmt.Sprintf("%s[%d]", baseName, element)
+			location := glc.GetUniformLocation(s.id, gl.Str(elementName+"\x00"))
+			values[elementName] = s.uniformValue(location, uniformType)
+		}
+	}
+	return values
+}
+
+// Number of values contained in the uniforms of each float type
+var floatUniformComponents = map[uint32]int{
+	gl.FLOAT:      1,
+	gl.FLOAT_VEC2: 2,
+	gl.FLOAT_VEC3: 3,
+	gl.FLOAT_VEC4: 4,
+	gl.FLOAT_MAT2: 4,
+	gl.FLOAT_MAT3: 9,
+	gl.FLOAT_MAT4: 16,
+
+	gl.FLOAT_MAT2x3: 6,
+	gl.FLOAT_MAT2x4: 8,
+	gl.FLOAT_MAT3x2: 6,
+	gl.FLOAT_MAT3x4: 12,
+	gl.FLOAT_MAT4x2: 8,
+	gl.FLOAT_MAT4x3: 12,
+}
+
+// Number of values contained in the uniforms of each integer type
+var intUniformComponents = map[uint32]int{
+	gl.INT:       1,
+	gl.INT_VEC2:  2,
+	gl.INT_VEC3:  3,
+	gl.INT_VEC4:  4,
+	gl.BOOL:      1,
+	gl.BOOL_VEC2: 2,
+	gl.BOOL_VEC3: 3,
+	gl.BOOL_VEC4: 4,
+}
+
+// Number of values contained in the uniforms of each unsigned integer type
+var uintUniformComponents = map[uint32]int{
+	gl.UNSIGNED_INT:      1,
+	gl.UNSIGNED_INT_VEC2: 2,
+	gl.UNSIGNED_INT_VEC3: 3,
+	gl.UNSIGNED_INT_VEC4: 4,
+}
+
+// Number of values contained in the uniforms of each double type
+var doubleUniformComponents = map[uint32]int{
+	gl.DOUBLE:        1,
+	gl.DOUBLE_VEC2:   2,
+	gl.DOUBLE_VEC3:   3,
+	gl.DOUBLE_VEC4:   4,
+	gl.DOUBLE_MAT2:   4,
+	gl.DOUBLE_MAT3:   9,
+	gl.DOUBLE_MAT4:   16,
+	gl.DOUBLE_MAT2x3: 6,
+	gl.DOUBLE_MAT2x4: 8,
+	gl.DOUBLE_MAT3x2: 6,
+	gl.DOUBLE_MAT3x4: 12,
+	gl.DOUBLE_MAT4x2: 8,
+	gl.DOUBLE_MAT4x3: 12,
+}
+
+// uniformValue reads the value of a uniform and formats it as text
+func (s *ShaderProgram) uniformValue(location int32, uniformType uint32) string {
+	if components, found := floatUniformComponents[uniformType]; found {
+		var value [16]float32
+		glc.GetUniformfv(s.id, location, &value[0])
+		return fmt.Sprint(value[:components])
+	}
+	if components, found := intUniformComponents[uniformType]; found {
+		var value [4]int32
+		glc.GetUniformiv(s.id, location, &value[0])
+		return fmt.Sprint(value[:components])
+	}
+	if components, found := uintUniformComponents[uniformType]; found {
+		var value [4]uint32
+		glc.GetUniformuiv(s.id, location, &value[0])
+		return fmt.Sprint(value[:components])
+	}
+	if components, found := doubleUniformComponents[uniformType]; found {
+		var value [16]float64
+		glc.GetUniformdv(s.id, location, &value[0])
+		return fmt.Sprint(value[:components])
+	}
+	// Samplers are integers too, their value is the texture unit
+	var unit int32
+	glc.GetUniformiv(s.id, location, &unit)
+	return fmt.Sprintf("unit %d (type 0x%x)", unit, uniformType)
+}
+
 // ID returns the OpenGL ID assigned to this shader program
 func (s *ShaderProgram) ID() uint32 {
 	return s.id
@@ -123,44 +333,146 @@ func (s *ShaderProgram) GetUniform(name string) int32 {
 	uniform, found := s.uniforms[name]
 	if !found {
 		cname := gl.Str(name + "\x00")
-		uniform = gl.GetUniformLocation(s.id, cname)
+		uniform = glc.GetUniformLocation(s.id, cname)
 		s.uniforms[name] = uniform
 	}
 
 	return uniform
 }
 
+// Time passed to the shaders of the primitives, see SetShaderTime
+var shaderTime float32
+
+// SetShaderTime sets the time, in seconds, passed to the 'time' uniform of the shaders of the primitives when they
+// are drawn. Shaders not declaring the uniform are skipped. Call it once per frame for animated effects
+func SetShaderTime(seconds float32) {
+	shaderTime = seconds
+}
+
+// ShaderTime returns the time set with SetShaderTime
+func ShaderTime() float32 {
+	return shaderTime
+}
+
 // SetUniform sets the shader's uniforms based on the type of the value passed
 func (s *ShaderProgram) SetUniform(name string, val interface{}) {
+	if err := setUniformValue(s.GetUniform(name), val); err != nil {
+		fmt.Printf("Error: %s", err)
+	}
+}
+
+// getArrayUniform returns the location of the first element of an array uniform. Both 'name' and 'name[0]' are
+// accepted, as in GLSL
+func (s *ShaderProgram) getArrayUniform(name string) int32 {
 	uniform := s.GetUniform(name)
+	if uniform < 0 && !strings.HasSuffix(name, "]") {
+		uniform = s.GetUniform(name + "[0]")
+	}
+	return uniform
+}
+
+// SetUniformFloatArray sets the elements of a float array uniform, starting from the first one
+func (s *ShaderProgram) SetUniformFloatArray(name string, values []float32) {
+	if len(values) == 0 {
+		return
+	}
+	glc.Uniform1fv(s.getArrayUniform(name), int32(len(values)), &values[0])
+}
+
+// SetUniformVec2Array sets the elements of a vec2 array uniform, starting from the first one
+func (s *ShaderProgram) SetUniformVec2Array(name string, values []mgl32.Vec2) {
+	if len(values) == 0 {
+		return
+	}
+	glc.Uniform2fv(s.getArrayUniform(name), int32(len(values)), &values[0][0])
+}
+
+// SetUniformVec3Array sets the elements of a vec3 array uniform, starting from the first one
+func (s *ShaderProgram) SetUniformVec3Array(name string, values []mgl32.Vec3) {
+	if len(values) == 0 {
+		return
+	}
+	glc.Uniform3fv(s.getArrayUniform(name), int32(len(values)), &values[0][0])
+}
+
+// SetUniformVec4Array sets the elements of a vec4 array uniform, starting from the first one
+func (s *ShaderProgram) SetUniformVec4Array(name string, values []mgl32.Vec4) {
+	if len(values) == 0 {
+		return
+	}
+	glc.Uniform4fv(s.getArrayUniform(name), int32(len(values)), &values[0][0])
+}
+
+// SetUniforms sets many uniforms at once. Uniforms not found in the program and values of unsupported types are
+// skipped and reported in the returned error
+func (s *ShaderProgram) SetUniforms(values map[string]interface{}) error {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var problems []string
+	for _, name := range names {
+		uniform := s.GetUniform(name)
+		if uniform < 0 {
+			problems = append(problems, fmt.Sprintf("uniform '%s' not found", name))
+			continue
+		}
+		if err := setUniformValue(uniform, values[name]); err != nil {
+			problems = append(problems, fmt.Sprintf("uniform '%s': %s", name, err))
+		}
+	}
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+func setUniformValue(uniform int32, val interface{}) error {
 	switch v := val.(type) {
+	case int32:
+		return setUniformValue(uniform, &v)
+	case float32:
+		return setUniformValue(uniform, &v)
+	case mgl32.Vec2:
+		return setUniformValue(uniform, &v)
+	case mgl32.Vec3:
+		return setUniformValue(uniform, &v)
+	case mgl32.Vec4:
+		return setUniformValue(uniform, &v)
+	case mgl32.Mat2:
+		return setUniformValue(uniform, &v)
+	case mgl32.Mat3:
+		return setUniformValue(uniform, &v)
+	case mgl32.Mat4:
+		return setUniformValue(uniform, &v)
+	case Color:
+		return setUniformValue(uniform, &v)
+	case *int32:
+		glc.Uniform1iv(uniform, 1, v)
 	case *float32:
-		gl.Uniform1fv(uniform, 1, v)
+		glc.Uniform1fv(uniform, 1, v)
 	case *mgl32.Vec2:
-		gl.Uniform2fv(uniform, 1, &(*v)[0])
+		glc.Uniform2fv(uniform, 1, &(*v)[0])
 	case *mgl32.Vec3:
-		gl.Uniform3fv(uniform, 1, &(*v)[0])
+		glc.Uniform3fv(uniform, 1, &(*v)[0])
 	case *mgl32.Vec4:
-		gl.Uniform4fv(uniform, 1, &(*v)[0])
+		glc.Uniform4fv(uniform, 1, &(*v)[0])
 	case *mgl32.Mat2:
-		gl.UniformMatrix2fv(uniform, 1, false, &(*v)[0])
+		glc.UniformMatrix2fv(uniform, 1, false, &(*v)[0])
 	case *mgl32.Mat3:
-		gl.UniformMatrix3fv(uniform, 1, false, &(*v)[0])
+		glc.UniformMatrix3fv(uniform, 1, false, &(*v)[0])
 	case *mgl32.Mat4:
-		gl.UniformMatrix4fv(uniform, 1, false, &(*v)[0])
+		glc.UniformMatrix4fv(uniform, 1, false, &(*v)[0])
 	case *Color:
-		gl.Uniform4fv(uniform, 1, &(*v)[0])
-	case *float64:
-	case *mgl64.Vec2:
-	case *mgl64.Vec3:
-	case *mgl64.Vec4:
-	case *mgl64.Mat2:
-	case *mgl64.Mat3:
-	case *mgl64.Mat4:
-		fmt.Printf("Error: this method accepts only float32 values. Value type: %T %+v", val, val)
+		glc.Uniform4fv(uniform, 1, &(*v)[0])
+	case *float64, *mgl64.Vec2, *mgl64.Vec3, *mgl64.Vec4, *mgl64.Mat2, *mgl64.Mat3, *mgl64.Mat4:
+		return fmt.Errorf("this method accepts only float32 values. Value type: %T %+v", val, val)
 	default:
-		fmt.Printf("Error: unknown value type: %T %+v", val, val)
+		return fmt.Errorf("unknown value type: %T %+v", val, val)
 	}
+	return nil
 }
 
 const (
@@ -183,6 +495,47 @@ const (
         }
         ` + "\x00"
 
+	// VertexShaderView is like VertexShaderBase, with the view transformation separated from the projection
+	VertexShaderView = `
+        #version 410 core
+
+        uniform mat4 model;
+        uniform mat4 view;
+        uniform mat4 projection;
+
+        layout(location=0) in vec2 vertex;
+        layout(location=1) in vec2 uv;
+
+        out vec2 uv_out;
+
+        void main() {
+            vec4 vertex_world = model * vec4(vertex, 0, 1);
+            gl_Position = projection * view * vertex_world;
+            uv_out = uv;
+        }
+        ` + "\x00"
+
+	// VertexShaderUVTransform is like VertexShaderBase, with the UV coordinates transformed by the 'uv_transform'
+	// uniform (see Primitive2D.SetUVTransform)
+	VertexShaderUVTransform = `
+        #version 410 core
+
+        uniform mat4 model;
+        uniform mat4 projection;
+        uniform mat3 uv_transform;
+
+        layout(location=0) in vec2 vertex;
+        layout(location=1) in vec2 uv;
+
+        out vec2 uv_out;
+
+        void main() {
+            vec4 vertex_world = model * vec4(vertex, 0, 1);
+            gl_Position = projection * vertex_world;
+            uv_out = (uv_transform * vec3(uv, 1)).xy;
+        }
+        ` + "\x00"
+
 	// FragmentShaderSolidColor used to have a solid color shape/primitive
 	FragmentShaderSolidColor = `
         #version 410 core
@@ -211,4 +564,110 @@ const (
             color = texture(tex, uv_out);
         }
         ` + "\x00"
+
+	// VertexShaderVertexColor passes a per-vertex color (attribute 2) to the fragment shader
+	VertexShaderVertexColor = `
+        #version 410 core
+
+        uniform mat4 model;
+        uniform mat4 projection;
+
+        layout(location=0) in vec2 vertex;
+        layout(location=1) in vec2 uv;
+        layout(location=2) in vec4 vertex_color;
+
+        out vec2 uv_out;
+        out vec4 color_out;
+
+        void main() {
+            vec4 vertex_world = model * vec4(vertex, 0, 1);
+            gl_Position = projection * vertex_world;
+            uv_out = uv;
+            color_out = vertex_color;
+        }
+        ` + "\x00"
+
+	// FragmentShaderVertexColor fills the primitive with the colors interpolated between its vertices
+	FragmentShaderVertexColor = `
+        #version 410 core
+
+        in vec2 uv_out;
+        in vec4 color_out;
+        out vec4 out_color;
+
+        void main() {
+            out_color = color_out;
+        }
+        ` + "\x00"
+
+	// VertexShaderTwoUVs passes both the UV sets (attributes 1 and 3) to the fragment shader
+	VertexShaderTwoUVs = `
+        #version 410 core
+
+        uniform mat4 model;
+        uniform mat4 projection;
+
+        layout(location=0) in vec2 vertex;
+        layout(location=1) in vec2 uv;
+        layout(location=3) in vec2 uv2;
+
+        out vec2 uv_out;
+        out vec2 uv2_out;
+
+        void main() {
+            vec4 vertex_world = model * vec4(vertex, 0, 1);
+            gl_Position = projection * vertex_world;
+            uv_out = uv;
+            uv2_out = uv2;
+        }
+        ` + "\x00"
+
+	// FragmentShaderTwoTextures multiplies the texture 'tex' (first UV set) with the texture 'tex2' (second UV set)
+	FragmentShaderTwoTextures = `
+        #version 410 core
+
+        in vec2 uv_out;
+        in vec2 uv2_out;
+        out vec4 color;
+
+        uniform sampler2D tex;
+        uniform sampler2D tex2;
+
+        void main() {
+            color = texture(tex, uv_out) * texture(tex2, uv2_out);
+        }
+        ` + "\x00"
+
+	// VertexShaderPoints sets the size of the points drawn with gl.POINTS from the 'point_size' uniform
+	VertexShaderPoints = `
+        #version 410 core
+
+        uniform mat4 model;
+        uniform mat4 projection;
+        uniform float point_size;
+
+        layout(location=0) in vec2 vertex;
+
+        void main() {
+            vec4 vertex_world = model * vec4(vertex, 0, 1);
+            gl_Position = projection * vertex_world;
+            gl_PointSize = point_size;
+        }
+        ` + "\x00"
+
+	// FragmentShaderRoundPoint draws the points as solid color circles
+	FragmentShaderRoundPoint = `
+        #version 410 core
+
+        out vec4 out_color;
+        uniform vec4 color;
+
+        void main() {
+            vec2 position = gl_PointCoord * 2.0 - 1.0;
+            if (dot(position, position) > 1.0) {
+                discard;
+            }
+            out_color = color;
+        }
+        ` + "\x00"
 )
@@ -0,0 +1,80 @@
+package gl_utils
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// countCalls returns how many times the function named has been called
+func countCalls(r *recordingGLContext, name string) int {
+	count := 0
+	for _, call := range r.calls {
+		if strings.HasPrefix(call, name+"(") {
+			count++
+		}
+	}
+	return count
+}
+
+func TestReleaseSharedProgram(t *testing.T) {
+	r := useRecordingGL(t)
+	shader := SharedShaderProgram(VertexShaderBase, "", FragmentShaderSolidColor)
+	shader.Release()
+	shader.Release()
+	if count := countCalls(r, "DeleteProgram"); count != 0 {
+		t.Errorf("a shared program has been deleted %d times", count)
+	}
+	if SharedShaderProgram(VertexShaderBase, "", FragmentShaderSolidColor) != shader {
+		t.Error("the released program has been dropped from the cache")
+	}
+}
+
+func TestReleaseProgramTwice(t *testing.T) {
+	r := useRecordingGL(t)
+	shader := NewShaderProgram(VertexShaderBase, "", FragmentShaderSolidColor)
+	shader.Release()
+	shader.Release()
+	if count := countCalls(r, "DeleteProgram"); count != 1 {
+		t.Errorf("the program has been deleted %d times, expected once", count)
+	}
+}
+
+func TestDumpUniformsTypes(t *testing.T) {
+	r := useRecordingGL(t)
+	tests := []struct {
+		uniform  recordedUniform
+		glslType string
+		expected string
+	}{
+		{recordedUniform{"a_float", gl.FLOAT, float32(0.5)}, "float", "[0.5]"},
+		{recordedUniform{"a_vec3", gl.FLOAT_VEC3, float32(1.5)}, "vec3", "[1.5 0 0]"},
+		{recordedUniform{"a_mat2x3", gl.FLOAT_MAT2x3, float32(2)}, "mat2x3", "[2 0 0 0 0 0]"},
+		{recordedUniform{"an_int", gl.INT, int32(-3)}, "int", "[-3]"},
+		{recordedUniform{"a_bvec2", gl.BOOL_VEC2, int32(1)}, "bvec2", "[1 0]"},
+		{recordedUniform{"a_uint", gl.UNSIGNED_INT, uint32(4000000000)}, "uint", "[4000000000]"},
+		{recordedUniform{"a_uvec4", gl.UNSIGNED_INT_VEC4, uint32(7)}, "uvec4", "[7 0 0 0]"},
+		{recordedUniform{"a_double", gl.DOUBLE, 0.25}, "double", "[0.25]"},
+		{recordedUniform{"a_dvec2", gl.DOUBLE_VEC2, 1.25}, "dvec2", "[1.25 0]"},
+		{recordedUniform{"a_dmat3", gl.DOUBLE_MAT3, 2.5}, "dmat3", "[2.5 0 0 0 0 0 0 0 0]"},
+		{recordedUniform{"a_sampler", gl.SAMPLER_2D, int32(2)}, "sampler2D", fmt.Sprintf("unit 2 (type 0x%x)", gl.SAMPLER_2D)},
+	}
+	var declarations, uses strings.Builder
+	for _, test := range tests {
+		r.activeUniforms = append(r.activeUniforms, test.uniform)
+		fmt.Fprintf(&declarations, "uniform %s %s;\n", test.glslType, test.uniform.name)
+		fmt.Fprintf(&uses, "use(%s);\n", test.uniform.name)
+	}
+	fragment := "#version 410 core\n" + declarations.String() + "void main() {\n" + uses.String() + "}\n\x00"
+	shader := NewShaderProgram(VertexShaderBase, "", fragment)
+
+	values := shader.DumpUniforms()
+
+	for _, test := range tests {
+		if value := values[test.uniform.name]; value != test.expected {
+			t.Errorf("%s: %q, expected %q", test.uniform.name, value, test.expected)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package gl_utils
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// SmoothCirclePrimitive a circle drawn as a single quad, the fragment shader computes the coverage of each pixel
+// from its distance to the center. The edge is antialiased at any zoom level
+type SmoothCirclePrimitive struct {
+	Primitive2D
+	radius   float32
+	softness float32
+}
+
+// NewSmoothCirclePrimitive creates a circle filled with the primitive color, anchored to its center
+func NewSmoothCirclePrimitive(center mgl32.Vec3, radius float32) *SmoothCirclePrimitive {
+	c := &SmoothCirclePrimitive{
+		Primitive2D: *NewQuadPrimitiveExt(center, mgl32.Vec2{radius * 2, radius * 2}, nil, nil, nil),
+	}
+	c.SetAnchorToCenter()
+	// The pixels on the edge are blended with the background
+	c.SetTransparent(true)
+	c.SetMaterial(NewMaterial(SharedShaderProgram(VertexShaderBase, "", FragmentShaderCircle)))
+	c.SetRadius(radius)
+	c.SetSoftness(0)
+	return c
+}
+
+// Radius returns the radius of the circle
+func (c *SmoothCirclePrimitive) Radius() float32 {
+	return c.radius
+}
+
+// SetRadius sets the radius of the circle, resizing the quad it's drawn on
+func (c *SmoothCirclePrimitive) SetRadius(radius float32) {
+	c.radius = radius
+	c.SetSize(mgl32.Vec2{radius * 2, radius * 2})
+	c.material.SetUniform("radius", radius)
+}
+
+// Softness returns the width of the faded edge of the circle
+func (c *SmoothCirclePrimitive) Softness() float32 {
+	return c.softness
+}
+
+// SetSoftness sets the width of the faded edge of the circle, in the same units of the radius. With 0 the edge is
+// just antialiased, larger values blur it
+func (c *SmoothCirclePrimitive) SetSoftness(softness float32) {
+	c.softness = softness
+	c.material.SetUniform("softness", softness)
+}
+
+// SmoothRoundedRectPrimitive a rectangle with rounded corners drawn as a single quad, the fragment shader computes
+// the signed distance from the shape. Edges and corners are antialiased at any zoom level
+type SmoothRoundedRectPrimitive struct {
+	Primitive2D
+	cornerRadius float32
+	softness     float32
+}
+
+// NewSmoothRoundedRectPrimitive creates a rounded rectangle filled with the primitive color
+func NewSmoothRoundedRectPrimitive(position mgl32.Vec3, size mgl32.Vec2, cornerRadius float32) *SmoothRoundedRectPrimitive {
+	r := &SmoothRoundedRectPrimitive{
+		Primitive2D: *NewQuadPrimitiveExt(position, size, nil, nil, nil),
+	}
+	// The pixels on the edge are blended with the background
+	r.SetTransparent(true)
+	r.SetMaterial(NewMaterial(SharedShaderProgram(VertexShaderBase, "", FragmentShaderRoundedRect)))
+	r.SetSize(size)
+	r.SetCornerRadius(cornerRadius)
+	r.SetSoftness(0)
+	return r
+}
+
+// SetSize sets the size of the rectangle
+func (r *SmoothRoundedRectPrimitive) SetSize(size mgl32.Vec2) {
+	r.Primitive2D.SetSize(size)
+	r.material.SetUniform("size", size)
+}
+
+// CornerRadius returns the radius of the corners
+func (r *SmoothRoundedRectPrimitive) CornerRadius() float32 {
+	return r.cornerRadius
+}
+
+// SetCornerRadius sets the radius of the corners. It's limited by the shader to half of the shortest side
+func (r *SmoothRoundedRectPrimitive) SetCornerRadius(radius float32) {
+	r.cornerRadius = radius
+	r.material.SetUniform("corner_radius", radius)
+}
+
+// Softness returns the width of the faded edge of the rectangle
+func (r *SmoothRoundedRectPrimitive) Softness() float32 {
+	return r.softness
+}
+
+// SetSoftness sets the width of the faded edge of the rectangle, in the same units of the size. With 0 the edge is
+// just antialiased, larger values blur it
+func (r *SmoothRoundedRectPrimitive) SetSoftness(softness float32) {
+	r.softness = softness
+	r.material.SetUniform("softness", softness)
+}
+
+const (
+	// FragmentShaderCircle fills the circle inscribed in the quad with the 'color' uniform. The 'radius' uniform is
+	// the radius of the circle and 'softness' the width of its faded edge, both in model units
+	FragmentShaderCircle = `
+        #version 410 core
+
+        in vec2 uv_out;
+        out vec4 out_color;
+        uniform vec4 color;
+        uniform float radius;
+        uniform float softness;
+
+        void main() {
+            float distance = length(uv_out * 2.0 - 1.0) * radius;
+            float width = max(softness, fwidth(distance));
+            float alpha = 1.0 - smoothstep(radius - width, radius, distance);
+            out_color = vec4(color.rgb, color.a * alpha);
+        }
+        ` + "\x00"
+
+	// FragmentShaderRoundedRect fills a rectangle with rounded corners with the 'color' uniform. The 'size' uniform is
+	// the size of the quad, 'corner_radius' the radius of the corners and 'softness' the width of the faded edge, all
+	// in model units
+	FragmentShaderRoundedRect = `
+        #version 410 core
+
+        in vec2 uv_out;
+        out vec4 out_color;
+        uniform vec4 color;
+        uniform vec2 size;
+        uniform float corner_radius;
+        uniform float softness;
+
+        void main() {
+            vec2 half_size = size * 0.5;
+            float radius = clamp(corner_radius, 0.0, min(half_size.x, half_size.y));
+            vec2 q = abs((uv_out - 0.5) * size) - half_size + radius;
+            float distance = length(max(q, 0.0)) + min(max(q.x, q.y), 0.0) - radius;
+            float width = max(softness, fwidth(distance));
+            float alpha = 1.0 - smoothstep(-width, 0.0, distance);
+            out_color = vec4(color.rgb, color.a * alpha);
+        }
+        ` + "\x00"
+)
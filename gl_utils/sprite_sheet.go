@@ -0,0 +1,84 @@
+package gl_utils
+
+import "fmt"
+
+// SpriteSheet a texture containing a grid of equally sized frames
+type SpriteSheet struct {
+	texture     *Texture
+	frameWidth  int
+	frameHeight int
+	margin      int
+	spacing     int
+	columns     int
+	rows        int
+}
+
+// NewSpriteSheet creates a sprite sheet where the frames are packed without any margin or spacing
+func NewSpriteSheet(texture *Texture, frameWidth, frameHeight int) *SpriteSheet {
+	return NewSpriteSheetExt(texture, frameWidth, frameHeight, 0, 0)
+}
+
+// NewSpriteSheetExt creates a sprite sheet. The margin is the space (in pixels) around the whole grid, the spacing
+// is the space between two adjacent frames. It returns nil if the texture is missing or the sizes are invalid
+func NewSpriteSheetExt(texture *Texture, frameWidth, frameHeight int, margin int, spacing int) *SpriteSheet {
+	if texture == nil {
+		fmt.Println("Error: a sprite sheet needs a texture")
+		return nil
+	}
+	if frameWidth <= 0 || frameHeight <= 0 || margin < 0 || spacing < 0 {
+		fmt.Printf("Error: invalid sprite sheet frame size %dx%d, margin %d, spacing %d\n", frameWidth, frameHeight, margin, spacing)
+		return nil
+	}
+	s := &SpriteSheet{
+		texture:     texture,
+		frameWidth:  frameWidth,
+		frameHeight: frameHeight,
+		margin:      margin,
+		spacing:     spacing,
+	}
+	s.columns = (int(texture.width) - margin*2 + spacing) / (frameWidth + spacing)
+	s.rows = (int(texture.height) - margin*2 + spacing) / (frameHeight + spacing)
+	return s
+}
+
+// Texture returns the texture containing the frames
+func (s *SpriteSheet) Texture() *Texture {
+	return s.texture
+}
+
+// FrameSize returns the size in pixels of a single frame
+func (s *SpriteSheet) FrameSize() (int, int) {
+	return s.frameWidth, s.frameHeight
+}
+
+// NumFrames returns the number of frames contained in the sheet
+func (s *SpriteSheet) NumFrames() int {
+	return s.columns * s.rows
+}
+
+// Frame returns the UV coordinates of the top-left and bottom-right corners of a frame. Frames are numbered left to
+// right, top to bottom. The index wraps around the number of frames
+func (s *SpriteSheet) Frame(index int) (u0, v0, u1, v1 float32) {
+	numFrames := s.NumFrames()
+	if numFrames == 0 {
+		return 0, 0, 0, 0
+	}
+	index = ((index % numFrames) + numFrames) % numFrames
+
+	x := s.margin + (index%s.columns)*(s.frameWidth+s.spacing)
+	y := s.margin + (index/s.columns)*(s.frameHeight+s.spacing)
+	width := float32(s.texture.width)
+	height := float32(s.texture.height)
+
+	u0 = float32(x) / width
+	v0 = float32(y) / height
+	u1 = float32(x+s.frameWidth) / width
+	v1 = float32(y+s.frameHeight) / height
+	return
+}
+
+// ApplyFrame sets the UV coordinates of a quad primitive to show the specified frame
+func (s *SpriteSheet) ApplyFrame(p *Primitive2D, index int) {
+	u0, v0, u1, v1 := s.Frame(index)
+	p.SetUVCoords([]float32{u0, v0, u0, v1, u1, v1, u1, v0})
+}
@@ -0,0 +1,32 @@
+package gl_utils
+
+// Stats counters about the work submitted to OpenGL
+type Stats struct {
+	// DrawCalls is the number of draw calls issued
+	DrawCalls int
+	// Vertices is the number of vertices submitted by the draw calls
+	Vertices int
+	// BufferUploads is the number of vertex buffer uploads
+	BufferUploads int
+}
+
+var stats Stats
+
+// GetStats returns the counters accumulated since the last call to ResetStats
+func GetStats() Stats {
+	return stats
+}
+
+// ResetStats sets all the counters to zero, usually called at the beginning of each frame
+func ResetStats() {
+	stats = Stats{}
+}
+
+func countDrawCall(numVertices int) {
+	stats.DrawCalls++
+	stats.Vertices += numVertices
+}
+
+func countBufferUpload() {
+	stats.BufferUploads++
+}
@@ -0,0 +1,303 @@
+package gl_utils
+
+import (
+	"strings"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// TextAlign the horizontal alignment of the lines of a text
+type TextAlign int
+
+// Text alignments supported
+const (
+	AlignLeft TextAlign = iota
+	AlignCenter
+	AlignRight
+)
+
+// TextPrimitive a string of text drawn with a bitmap font. The coordinates of the text are in pixels of the font,
+// with the origin at the top-left corner of the first line
+type TextPrimitive struct {
+	Primitive2D
+	font          *BitmapFont
+	text          string
+	maxWidth      float32
+	align         TextAlign
+	letterSpacing float32
+	lineSpacing   float32
+	boundsMin     mgl32.Vec2
+	boundsMax     mgl32.Vec2
+}
+
+// NewTextPrimitive creates a primitive drawing text with the font passed. The text is tinted with the primitive color
+func NewTextPrimitive(font *BitmapFont, position mgl32.Vec3, text string) *TextPrimitive {
+	shader := SharedShaderProgram(VertexShaderBase, "", FragmentShaderText)
+	t := &TextPrimitive{
+		Primitive2D: *newPrimitive2D(position, mgl32.Vec2{1, 1}, shader),
+		font:        font,
+		lineSpacing: 1,
+	}
+	t.arrayMode = gl.TRIANGLES
+	t.texture = font.texture
+	t.SetText(text)
+	return t
+}
+
+// LayoutText creates a text primitive wrapping the words of the text on lines not longer than maxWidth (no wrapping
+// if it's <= 0) and aligning the lines. Explicit newlines are respected. It returns the primitive and the corners of
+// the box containing the text, in the coordinates of the primitive
+func LayoutText(font *BitmapFont, text string, maxWidth float32, align TextAlign) (*TextPrimitive, mgl32.Vec2, mgl32.Vec2) {
+	t := NewTextPrimitive(font, mgl32.Vec3{}, "")
+	t.maxWidth = maxWidth
+	t.align = align
+	t.SetText(text)
+	return t, t.boundsMin, t.boundsMax
+}
+
+// Font returns the font used to draw the text
+func (t *TextPrimitive) Font() *BitmapFont {
+	return t.font
+}
+
+// Text returns the text drawn
+func (t *TextPrimitive) Text() string {
+	return t.text
+}
+
+// SetText changes the text drawn, rebuilding the geometry. Characters missing from the font are skipped
+func (t *TextPrimitive) SetText(text string) {
+	t.text = text
+	t.rebuildGeometry()
+}
+
+// MaxWidth returns the width the lines are wrapped at, 0 if they are not wrapped
+func (t *TextPrimitive) MaxWidth() float32 {
+	return t.maxWidth
+}
+
+// SetMaxWidth sets the width the lines are wrapped at, pass 0 to disable the wrapping
+func (t *TextPrimitive) SetMaxWidth(maxWidth float32) {
+	t.maxWidth = maxWidth
+	t.rebuildGeometry()
+}
+
+// Align returns the horizontal alignment of the lines
+func (t *TextPrimitive) Align() TextAlign {
+	return t.align
+}
+
+// SetAlign sets the horizontal alignment of the lines. The lines are aligned within MaxWidth if it's set, otherwise
+// within the longest line
+func (t *TextPrimitive) SetAlign(align TextAlign) {
+	t.align = align
+	t.rebuildGeometry()
+}
+
+// LetterSpacing returns the space added between consecutive characters, in pixels
+func (t *TextPrimitive) LetterSpacing() float32 {
+	return t.letterSpacing
+}
+
+// SetLetterSpacing sets the space added between consecutive characters, in pixels. Negative values tighten the text
+func (t *TextPrimitive) SetLetterSpacing(pixels float32) {
+	t.letterSpacing = pixels
+	t.rebuildGeometry()
+}
+
+// LineSpacing returns the distance between two lines, as a factor of the line height of the font
+func (t *TextPrimitive) LineSpacing() float32 {
+	return t.lineSpacing
+}
+
+// SetLineSpacing sets the distance between two lines, as a factor of the line height of the font (1 by default)
+func (t *TextPrimitive) SetLineSpacing(factor float32) {
+	t.lineSpacing = factor
+	t.rebuildGeometry()
+}
+
+// TextBounds returns the corners of the box containing the text, in the coordinates of the primitive
+func (t *TextPrimitive) TextBounds() (mgl32.Vec2, mgl32.Vec2) {
+	return t.boundsMin, t.boundsMax
+}
+
+// wrapLines splits the text in lines, at the newlines and where a line would get longer than maxWidth. A word
+// longer than maxWidth gets a line on its own
+func (t *TextPrimitive) wrapLines() []string {
+	lines := make([]string, 0)
+	for _, paragraph := range strings.Split(t.text, "\n") {
+		if t.maxWidth <= 0 {
+			lines = append(lines, paragraph)
+			continue
+		}
+		words := strings.Split(paragraph, " ")
+		line := words[0]
+		for _, word := range words[1:] {
+			candidate := line + " " + word
+			if line != "" && t.lineWidth(candidate) > t.maxWidth {
+				lines = append(lines, line)
+				line = word
+			} else {
+				line = candidate
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// lineWidth returns the horizontal space taken by a line of text
+func (t *TextPrimitive) lineWidth(line string) float32 {
+	var width float32
+	var previous rune
+	for _, char := range line {
+		glyph := t.font.glyphs[char]
+		if glyph == nil {
+			continue
+		}
+		width += t.spaceBefore(previous, char) + float32(glyph.XAdvance)
+		previous = char
+	}
+	return width
+}
+
+// spaceBefore returns the space between two characters, kerning plus letter spacing. It's 0 for the first character
+// of a line, the letter spacing goes only between characters
+func (t *TextPrimitive) spaceBefore(previous rune, char rune) float32 {
+	if previous == 0 {
+		return 0
+	}
+	return float32(t.font.Kerning(previous, char)) + t.letterSpacing
+}
+
+func (t *TextPrimitive) rebuildGeometry() {
+	textureWidth := float32(t.font.scaleW)
+	textureHeight := float32(t.font.scaleH)
+	lines := t.wrapLines()
+
+	widths := make([]float32, len(lines))
+	blockWidth := t.maxWidth
+	for i, line := range lines {
+		widths[i] = t.lineWidth(line)
+		if t.maxWidth <= 0 && widths[i] > blockWidth {
+			blockWidth = widths[i]
+		}
+	}
+
+	vertices := make([]float32, 0, len(t.text)*12)
+	uvCoords := make([]float32, 0, len(t.text)*12)
+	t.boundsMin = mgl32.Vec2{0, 0}
+	lineHeight := float32(t.font.lineHeight) * t.lineSpacing
+	t.boundsMax = mgl32.Vec2{0, float32(len(lines)) * lineHeight}
+	for lineIndex, line := range lines {
+		var penX float32
+		switch t.align {
+		case AlignCenter:
+			penX = (blockWidth - widths[lineIndex]) / 2
+		case AlignRight:
+			penX = blockWidth - widths[lineIndex]
+		}
+		if lineIndex == 0 || penX < t.boundsMin[0] {
+			t.boundsMin[0] = penX
+		}
+		if lineIndex == 0 || penX+widths[lineIndex] > t.boundsMax[0] {
+			t.boundsMax[0] = penX + widths[lineIndex]
+		}
+		penY := float32(lineIndex) * lineHeight
+
+		var previous rune
+		for _, char := range line {
+			glyph := t.font.glyphs[char]
+			if glyph == nil {
+				continue
+			}
+			penX += t.spaceBefore(previous, char)
+			previous = char
+			if glyph.Width > 0 && glyph.Height > 0 {
+				x0 := penX + float32(glyph.XOffset)
+				y0 := penY + float32(glyph.YOffset)
+				x1 := x0 + float32(glyph.Width)
+				y1 := y0 + float32(glyph.Height)
+				u0 := float32(glyph.X) / textureWidth
+				v0 := float32(glyph.Y) / textureHeight
+				u1 := float32(glyph.X+glyph.Width) / textureWidth
+				v1 := float32(glyph.Y+glyph.Height) / textureHeight
+				vertices = append(vertices, x0, y0, x0, y1, x1, y1, x0, y0, x1, y1, x1, y0)
+				uvCoords = append(uvCoords, u0, v0, u0, v1, u1, v1, u0, v0, u1, v1, u1, v0)
+			}
+			penX += float32(glyph.XAdvance)
+		}
+	}
+
+	if len(vertices) == 0 {
+		// Nothing to upload, an empty buffer can't be passed to OpenGL. The previous glyphs are forgotten, so Bounds
+		// and RenderQueue don't see them
+		t.vertices = nil
+		t.uvCoords = nil
+		t.arraySize = 0
+		return
+	}
+	t.SetVertices(vertices)
+	t.SetUVCoords(uvCoords)
+}
+
+// SetSDFParams switches the text to signed distance field rendering, for fonts whose texture contains a distance
+// field instead of the shape of the characters. The text stays crisp at any scale. The outline width is expressed in
+// distance field units (0 disables the outline, 0.5 is the whole spread of the field), softness blurs the edges
+func (t *TextPrimitive) SetSDFParams(outlineWidth, softness float32, outlineColor Color) {
+	if t.material == nil {
+		material := NewMaterial(SharedShaderProgram(VertexShaderBase, "", FragmentShaderSDFText))
+		material.SetTexture("tex", t.font.texture)
+		t.SetMaterial(material)
+	}
+	t.material.SetUniform("outline_width", outlineWidth)
+	t.material.SetUniform("softness", softness)
+	t.material.SetUniform("outline_color", outlineColor)
+}
+
+const (
+	// FragmentShaderText tints the characters of a bitmap font with the 'color' uniform
+	FragmentShaderText = `
+        #version 410 core
+
+        in vec2 uv_out;
+        out vec4 out_color;
+        uniform vec4 color;
+
+        uniform sampler2D tex;
+
+        void main() {
+            out_color = color * texture(tex, uv_out);
+        }
+        ` + "\x00"
+
+	// FragmentShaderSDFText draws text from a single channel signed distance field, where 0.5 is the edge of the
+	// characters. The edge is antialiased according to the screen-space rate of change of the field
+	FragmentShaderSDFText = `
+        #version 410 core
+
+        in vec2 uv_out;
+        out vec4 out_color;
+        uniform vec4 color;
+        uniform vec4 outline_color;
+        uniform float outline_width;
+        uniform float softness;
+
+        uniform sampler2D tex;
+
+        void main() {
+            float distance = texture(tex, uv_out).r;
+            float width = max(softness, fwidth(distance));
+            float fill_alpha = smoothstep(0.5 - width, 0.5 + width, distance);
+            if (outline_width <= 0.0) {
+                out_color = vec4(color.rgb, color.a * fill_alpha);
+                return;
+            }
+            float outline_edge = 0.5 - outline_width;
+            float outline_alpha = smoothstep(outline_edge - width, outline_edge + width, distance);
+            vec4 fill = mix(outline_color, color, fill_alpha);
+            out_color = vec4(fill.rgb, fill.a * outline_alpha);
+        }
+        ` + "\x00"
+)
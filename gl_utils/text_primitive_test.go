@@ -0,0 +1,78 @@
+package gl_utils
+
+import (
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// newTestFont returns a font whose characters 'a' and 'b' are 10 pixels wide and advance by 10 pixels
+func newTestFont() *BitmapFont {
+	return &BitmapFont{
+		texture:    &Texture{width: 64, height: 64},
+		lineHeight: 16,
+		scaleW:     64,
+		scaleH:     64,
+		glyphs: map[rune]*Glyph{
+			'a': {X: 0, Y: 0, Width: 10, Height: 16, XAdvance: 10},
+			'b': {X: 10, Y: 0, Width: 10, Height: 16, XAdvance: 10},
+		},
+		kerning: map[[2]rune]int{{'a', 'b'}: -2},
+	}
+}
+
+func TestLetterSpacingBetweenCharacters(t *testing.T) {
+	useRecordingGL(t)
+	text := NewTextPrimitive(newTestFont(), mgl32.Vec3{}, "aab")
+	text.SetLetterSpacing(3)
+
+	// Three advances, the kerning of 'ab' and the spacing between the characters only
+	expected := float32(10+10+10-2) + 3*2
+	if width := text.lineWidth("aab"); width != expected {
+		t.Errorf("lineWidth = %v, expected %v", width, expected)
+	}
+	if width := text.lineWidth("a"); width != 10 {
+		t.Errorf("lineWidth of a single character = %v, expected 10", width)
+	}
+	_, max := text.TextBounds()
+	if max.X() != expected {
+		t.Errorf("TextBounds width = %v, expected %v", max.X(), expected)
+	}
+	// The last character ends on the right edge of the bounds
+	last := text.vertices[len(text.vertices)-2]
+	if last != expected {
+		t.Errorf("the last character ends at %v, expected %v", last, expected)
+	}
+}
+
+func TestLetterSpacingRightAlignment(t *testing.T) {
+	useRecordingGL(t)
+	text, _, _ := LayoutText(newTestFont(), "ab\nb", 0, AlignRight)
+	text.SetLetterSpacing(4)
+	min, max := text.TextBounds()
+	// The first line is the widest: 10 + 10 - 2 of kerning + 4 of spacing
+	if min.X() != 0 || max.X() != 22 {
+		t.Errorf("TextBounds %v - %v, expected the lines between 0 and 22", min, max)
+	}
+	// The second line ends on the right edge
+	last := text.vertices[len(text.vertices)-2]
+	if last != 22 {
+		t.Errorf("the right aligned line ends at %v, expected 22", last)
+	}
+}
+
+func TestSetEmptyTextForgetsGlyphs(t *testing.T) {
+	useRecordingGL(t)
+	text := NewTextPrimitive(newTestFont(), mgl32.Vec3{}, "abab")
+	text.SetText("")
+	if text.ArraySize() != 0 || text.vertices != nil || text.uvCoords != nil {
+		t.Errorf("%d vertices drawn, %d vertex and %d UV coordinates kept", text.ArraySize(), len(text.vertices), len(text.uvCoords))
+	}
+	// Without glyphs the bounds fall back to the box of the primitive size
+	if min, max := text.Bounds(); min != (mgl32.Vec2{0, 0}) || max != (mgl32.Vec2{1, 1}) {
+		t.Errorf("Bounds() = %v, %v, expected the box of the size", min, max)
+	}
+	if text.mergeable() {
+		t.Error("an empty text can be merged by the render queue")
+	}
+}
@@ -11,6 +11,7 @@ import (
 	_ "image/png"
 
 	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
 )
 
 // Texture a representation of an image file in memory
@@ -18,10 +19,17 @@ type Texture struct {
 	id     uint32
 	width  int32
 	height int32
+	stream *textureStream
 }
 
 // NewTextureFromFile loads the image from a file into a texture
 func NewTextureFromFile(filePath string) *Texture {
+	return NewTextureFromFileExt(filePath, false)
+}
+
+// NewTextureFromFileExt loads the image from a file into a texture, optionally premultiplying its colors by alpha
+// (see NewTextureFromImageExt)
+func NewTextureFromFileExt(filePath string, premultiplyAlpha bool) *Texture {
 	file, err := os.Open(filePath)
 	if err != nil {
 		fmt.Printf("Error loading texture. %s\n", err)
@@ -34,7 +42,21 @@ func NewTextureFromFile(filePath string) *Texture {
 		fmt.Printf("Error decoding <%s> image: '%s'\n", format, filePath)
 		return nil
 	}
-	return NewTextureFromImage(decodedImage)
+	return NewTextureFromImageExt(decodedImage, premultiplyAlpha)
+}
+
+// NewTextureFromImageExt uses the data from an Image struct to create a texture, optionally premultiplying its
+// colors by alpha. Filtering a premultiplied texture doesn't bleed the color of the transparent pixels into the
+// edges of the opaque ones (the dark halos around sprites), but the texture must be drawn with BlendPremultiplied
+// and the shaders see colors already multiplied by alpha
+func NewTextureFromImageExt(imageData image.Image, premultiplyAlpha bool) *Texture {
+	if _, isGray := imageData.(*image.Gray16); premultiplyAlpha && !isGray {
+		// The pixels of image.RGBA are premultiplied by definition, drawing into it does the conversion
+		rgba := image.NewRGBA(imageData.Bounds())
+		draw.Draw(rgba, rgba.Bounds(), imageData, imageData.Bounds().Min, draw.Src)
+		imageData = rgba
+	}
+	return NewTextureFromImage(imageData)
 }
 
 // NewTextureFromImage uses the data from an Image struct to create a texture
@@ -43,13 +65,13 @@ func NewTextureFromImage(imageData image.Image) *Texture {
 		width:  int32(imageData.Bounds().Dx()),
 		height: int32(imageData.Bounds().Dy()),
 	}
-	gl.GenTextures(1, &texture.id)
-	gl.ActiveTexture(gl.TEXTURE0)
-	gl.BindTexture(gl.TEXTURE_2D, texture.id)
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
+	glc.GenTextures(1, &texture.id)
+	glc.ActiveTexture(gl.TEXTURE0)
+	glc.BindTexture(gl.TEXTURE_2D, texture.id)
+	glc.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
+	glc.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
+	glc.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
+	glc.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
 
 	switch imageData.(type) {
 	case *image.Gray16:
@@ -60,14 +82,11 @@ func NewTextureFromImage(imageData image.Image) *Texture {
 			return nil
 		}
 		draw.Draw(grayImage, grayImage.Bounds(), imageData, image.Point{0, 0}, draw.Src)
-		gl.TexImage2D(
-			gl.TEXTURE_2D, 0, gl.RED, texture.width, texture.height,
-			0, gl.RED, gl.UNSIGNED_BYTE, gl.Ptr(grayImage.Pix),
-		)
+		texImage2D(gl.RED, texture.width, texture.height, gl.RED, 1, grayImage.Pix)
 	case *image.NRGBA:
 		// non-alpha-premultiplied 32-bit color image --> RGBA
 		pixelData := imageData.(*image.NRGBA).Pix
-		gl.TexImage2D(
+		glc.TexImage2D(
 			gl.TEXTURE_2D, 0, gl.RGBA, texture.width, texture.height,
 			0, gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(pixelData),
 		)
@@ -79,17 +98,74 @@ func NewTextureFromImage(imageData image.Image) *Texture {
 			return nil
 		}
 		draw.Draw(rgba, rgba.Bounds(), imageData, image.Point{0, 0}, draw.Src)
-		gl.TexImage2D(
+		glc.TexImage2D(
 			gl.TEXTURE_2D, 0, gl.RGBA, texture.width, texture.height,
 			0, gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(rgba.Pix),
 		)
 	}
 
-	gl.BindTexture(gl.TEXTURE_2D, 0)
+	glc.BindTexture(gl.TEXTURE_2D, 0)
 
 	return texture
 }
 
+// NewTextureFromPixels creates a texture from tightly packed 8-bit pixels. The format can be gl.RED, gl.RG, gl.RGB
+// or gl.RGBA, rows whose length isn't a multiple of 4 bytes are supported
+func NewTextureFromPixels(width int, height int, format uint32, pixels []byte) (*Texture, error) {
+	bytesPerPixel, found := bytesPerPixelFormat[format]
+	if !found {
+		return nil, fmt.Errorf("unsupported pixel format 0x%x", format)
+	}
+	if len(pixels) != width*height*bytesPerPixel {
+		return nil, fmt.Errorf("expected %d bytes of pixels, got %d", width*height*bytesPerPixel, len(pixels))
+	}
+
+	texture := &Texture{
+		width:  int32(width),
+		height: int32(height),
+	}
+	glc.GenTextures(1, &texture.id)
+	glc.ActiveTexture(gl.TEXTURE0)
+	glc.BindTexture(gl.TEXTURE_2D, texture.id)
+	glc.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
+	glc.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
+	glc.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
+	glc.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
+	texImage2D(int32(format), texture.width, texture.height, format, bytesPerPixel, pixels)
+	glc.BindTexture(gl.TEXTURE_2D, 0)
+
+	return texture, nil
+}
+
+// Size in bytes of a pixel, for the formats accepted by NewTextureFromPixels
+var bytesPerPixelFormat = map[uint32]int{
+	gl.RED:  1,
+	gl.RG:   2,
+	gl.RGB:  3,
+	gl.RGBA: 4,
+}
+
+// texImage2D uploads the pixels to the bound texture, lowering the unpack alignment for the upload when the rows
+// need it (see unpackAlignment)
+func texImage2D(internalFormat int32, width int32, height int32, format uint32, bytesPerPixel int, pixels []byte) {
+	if alignment := unpackAlignment(int(width) * bytesPerPixel); alignment != 4 {
+		var previous int32
+		glc.GetIntegerv(gl.UNPACK_ALIGNMENT, &previous)
+		glc.PixelStorei(gl.UNPACK_ALIGNMENT, alignment)
+		defer glc.PixelStorei(gl.UNPACK_ALIGNMENT, previous)
+	}
+	glc.TexImage2D(gl.TEXTURE_2D, 0, internalFormat, width, height, 0, format, gl.UNSIGNED_BYTE, gl.Ptr(pixels))
+}
+
+// unpackAlignment returns the unpack alignment to upload tightly packed rows of rowLength bytes. OpenGL expects by
+// default each row to start on a 4 bytes boundary, rows whose length isn't a multiple of 4 need an alignment of 1
+func unpackAlignment(rowLength int) int32 {
+	if rowLength%4 != 0 {
+		return 1
+	}
+	return 4
+}
+
 // NewEmptyTexture creates an empty texture with a specified size
 func NewEmptyTexture(width int, height int, pixelFormat int32) (*Texture, error) {
 	bounds := image.Rectangle{
@@ -102,28 +178,49 @@ func NewEmptyTexture(width int, height int, pixelFormat int32) (*Texture, error)
 		width:  int32(imageData.Bounds().Dx()),
 		height: int32(imageData.Bounds().Dy()),
 	}
-	gl.GenTextures(1, &texture.id)
-	gl.ActiveTexture(gl.TEXTURE0)
-	gl.BindTexture(gl.TEXTURE_2D, texture.id)
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
-	gl.TexImage2D(
+	glc.GenTextures(1, &texture.id)
+	glc.ActiveTexture(gl.TEXTURE0)
+	glc.BindTexture(gl.TEXTURE_2D, texture.id)
+	glc.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
+	glc.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
+	glc.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
+	glc.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
+	glc.TexImage2D(
 		gl.TEXTURE_2D, 0, pixelFormat, texture.width, texture.height,
 		0, uint32(pixelFormat), gl.UNSIGNED_BYTE, gl.Ptr(imageData.Pix),
 	)
-	gl.BindTexture(gl.TEXTURE_2D, 0)
+	glc.BindTexture(gl.TEXTURE_2D, 0)
 
 	return texture, nil
 }
 
 func (t *Texture) Bind() {
-	gl.BindTexture(gl.TEXTURE_2D, t.id)
+	glc.BindTexture(gl.TEXTURE_2D, t.id)
+}
+
+// BindToUnit binds the texture to the specified texture unit, leaving that unit active
+func (t *Texture) BindToUnit(unit int32) {
+	glc.ActiveTexture(gl.TEXTURE0 + uint32(unit))
+	glc.BindTexture(gl.TEXTURE_2D, t.id)
+}
+
+// SetWrap sets how the texture is sampled outside the 0-1 range on the two axes (e.g. gl.REPEAT, gl.CLAMP_TO_EDGE)
+func (t *Texture) SetWrap(wrapS int32, wrapT int32) {
+	glc.BindTexture(gl.TEXTURE_2D, t.id)
+	glc.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, wrapS)
+	glc.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, wrapT)
+	glc.BindTexture(gl.TEXTURE_2D, 0)
 }
 
 func (t *Texture) Unbind() {
-	gl.BindTexture(gl.TEXTURE_2D, 0)
+	glc.BindTexture(gl.TEXTURE_2D, 0)
+}
+
+// Release deletes the texture and the buffers used to update it
+func (t *Texture) Release() {
+	t.releaseStream()
+	glc.DeleteTextures(1, &t.id)
+	t.id = 0
 }
 
 // ID returns the unique OpenGL ID of this texture
@@ -136,7 +233,12 @@ func (t *Texture) Width() int32 {
 	return t.width
 }
 
-// Height returns the texture width in pixels
+// Height returns the texture height in pixels
 func (t *Texture) Height() int32 {
 	return t.height
 }
+
+// Size returns the texture width and height in pixels
+func (t *Texture) Size() mgl32.Vec2 {
+	return mgl32.Vec2{float32(t.width), float32(t.height)}
+}
@@ -0,0 +1,119 @@
+package gl_utils
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// TextureArray a stack of RGBA images of the same size, sampled by layer index (GL_TEXTURE_2D_ARRAY)
+type TextureArray struct {
+	id        uint32
+	width     int32
+	height    int32
+	maxLayers int32
+	numLayers int32
+}
+
+// NewTextureArray creates an empty texture array able to contain up to maxLayers images of width x height pixels
+func NewTextureArray(width int, height int, maxLayers int) *TextureArray {
+	t := &TextureArray{
+		width:     int32(width),
+		height:    int32(height),
+		maxLayers: int32(maxLayers),
+	}
+	glc.GenTextures(1, &t.id)
+	glc.ActiveTexture(gl.TEXTURE0)
+	glc.BindTexture(gl.TEXTURE_2D_ARRAY, t.id)
+	glc.TexParameteri(gl.TEXTURE_2D_ARRAY, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
+	glc.TexParameteri(gl.TEXTURE_2D_ARRAY, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
+	glc.TexParameteri(gl.TEXTURE_2D_ARRAY, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
+	glc.TexParameteri(gl.TEXTURE_2D_ARRAY, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
+	glc.TexImage3D(
+		gl.TEXTURE_2D_ARRAY, 0, gl.RGBA, t.width, t.height, t.maxLayers,
+		0, gl.RGBA, gl.UNSIGNED_BYTE, nil,
+	)
+	glc.BindTexture(gl.TEXTURE_2D_ARRAY, 0)
+	return t
+}
+
+// AddLayer uploads RGBA pixels (4 bytes per pixel) into the next free layer and returns its index
+func (t *TextureArray) AddLayer(pixels []byte) (int, error) {
+	if t.numLayers >= t.maxLayers {
+		return -1, errors.New("the texture array is full")
+	}
+	if len(pixels) != int(t.width*t.height*4) {
+		return -1, fmt.Errorf("expected %d bytes of RGBA pixels, got %d", t.width*t.height*4, len(pixels))
+	}
+	glc.BindTexture(gl.TEXTURE_2D_ARRAY, t.id)
+	glc.TexSubImage3D(
+		gl.TEXTURE_2D_ARRAY, 0, 0, 0, t.numLayers, t.width, t.height, 1,
+		gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(pixels),
+	)
+	glc.BindTexture(gl.TEXTURE_2D_ARRAY, 0)
+	t.numLayers++
+	return int(t.numLayers - 1), nil
+}
+
+// BindToUnit binds the texture array to the specified texture unit, leaving that unit active
+func (t *TextureArray) BindToUnit(unit int32) {
+	glc.ActiveTexture(gl.TEXTURE0 + uint32(unit))
+	glc.BindTexture(gl.TEXTURE_2D_ARRAY, t.id)
+}
+
+// SetSampler binds the texture array to a texture unit and assigns that unit to the named sampler of the shader.
+// The shader program must be in use
+func (t *TextureArray) SetSampler(shader *ShaderProgram, name string, unit int32) {
+	t.BindToUnit(unit)
+	shader.SetUniform(name, &unit)
+}
+
+// ID returns the unique OpenGL ID of this texture array
+func (t *TextureArray) ID() uint32 {
+	return t.id
+}
+
+// NumLayers returns the number of layers uploaded so far
+func (t *TextureArray) NumLayers() int {
+	return int(t.numLayers)
+}
+
+const (
+	// VertexShaderTextureArray passes the layer index of each vertex (attribute 4) to the fragment shader
+	VertexShaderTextureArray = `
+        #version 410 core
+
+        uniform mat4 model;
+        uniform mat4 projection;
+
+        layout(location=0) in vec2 vertex;
+        layout(location=1) in vec2 uv;
+        layout(location=4) in float layer;
+
+        out vec2 uv_out;
+        flat out float layer_out;
+
+        void main() {
+            vec4 vertex_world = model * vec4(vertex, 0, 1);
+            gl_Position = projection * vertex_world;
+            uv_out = uv;
+            layer_out = layer;
+        }
+        ` + "\x00"
+
+	// FragmentShaderTextureArray samples the layer of the 'tex_array' sampler selected by the vertex attribute
+	FragmentShaderTextureArray = `
+        #version 410 core
+
+        in vec2 uv_out;
+        flat in float layer_out;
+        out vec4 color;
+
+        uniform sampler2DArray tex_array;
+
+        void main() {
+            color = texture(tex_array, vec3(uv_out, layer_out));
+        }
+        ` + "\x00"
+)
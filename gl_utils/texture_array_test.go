@@ -0,0 +1,27 @@
+package gl_utils
+
+import (
+	"testing"
+)
+
+func TestTextureArrayAddLayer(t *testing.T) {
+	useRecordingGL(t)
+	array := NewTextureArray(2, 2, 2)
+	layer := make([]byte, 2*2*4)
+
+	if _, err := array.AddLayer(layer[:4]); err == nil {
+		t.Error("a layer of the wrong size has been accepted")
+	}
+	for expected := 0; expected < 2; expected++ {
+		index, err := array.AddLayer(layer)
+		if err != nil || index != expected {
+			t.Errorf("AddLayer() = %d, %v, expected %d", index, err, expected)
+		}
+	}
+	if _, err := array.AddLayer(layer); err == nil {
+		t.Error("a layer has been added to a full array")
+	}
+	if array.NumLayers() != 2 {
+		t.Errorf("NumLayers() = %d, expected 2", array.NumLayers())
+	}
+}
@@ -0,0 +1,60 @@
+package gl_utils
+
+import (
+	"fmt"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// Number of pixel buffers used by UpdateAsync, alternated so the CPU never writes into a buffer the GPU is reading
+const textureStreamBuffers = 2
+
+// textureStream the pixel unpack buffers used to stream the content of a texture
+type textureStream struct {
+	pbos  [textureStreamBuffers]uint32
+	index int
+}
+
+// UpdateAsync replaces the content of the texture with RGBA pixels (4 bytes per pixel) without stalling the
+// pipeline: the pixels are copied into a pixel unpack buffer and the GPU transfers them into the texture while the
+// CPU goes on. Suited to textures changing every frame (e.g. video). If the buffer can't be mapped the pixels are
+// uploaded directly
+func (t *Texture) UpdateAsync(pixels []byte) error {
+	size := int(t.width * t.height * 4)
+	if len(pixels) != size {
+		return fmt.Errorf("expected %d bytes of RGBA pixels, got %d", size, len(pixels))
+	}
+	if t.stream == nil {
+		t.stream = &textureStream{}
+		glc.GenBuffers(textureStreamBuffers, &t.stream.pbos[0])
+	}
+	t.stream.index = (t.stream.index + 1) % textureStreamBuffers
+
+	glc.BindBuffer(gl.PIXEL_UNPACK_BUFFER, t.stream.pbos[t.stream.index])
+	// Orphan the previous storage, so the driver doesn't wait for a pending transfer from it
+	glc.BufferData(gl.PIXEL_UNPACK_BUFFER, size, nil, gl.STREAM_DRAW)
+	mapped := glc.MapBufferRange(gl.PIXEL_UNPACK_BUFFER, 0, size, gl.MAP_WRITE_BIT|gl.MAP_INVALIDATE_BUFFER_BIT)
+	glc.BindTexture(gl.TEXTURE_2D, t.id)
+	if mapped != nil {
+		copy((*[1 << 30]byte)(mapped)[:size:size], pixels)
+		glc.UnmapBuffer(gl.PIXEL_UNPACK_BUFFER)
+		// With a buffer bound the last argument is an offset inside the buffer
+		glc.TexSubImage2D(gl.TEXTURE_2D, 0, 0, 0, t.width, t.height, gl.RGBA, gl.UNSIGNED_BYTE, gl.PtrOffset(0))
+		glc.BindBuffer(gl.PIXEL_UNPACK_BUFFER, 0)
+	} else {
+		glc.BindBuffer(gl.PIXEL_UNPACK_BUFFER, 0)
+		glc.TexSubImage2D(gl.TEXTURE_2D, 0, 0, 0, t.width, t.height, gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(pixels))
+	}
+	glc.BindTexture(gl.TEXTURE_2D, 0)
+	countBufferUpload()
+	return nil
+}
+
+// releaseStream deletes the pixel buffers used by UpdateAsync
+func (t *Texture) releaseStream() {
+	if t.stream == nil {
+		return
+	}
+	glc.DeleteBuffers(textureStreamBuffers, &t.stream.pbos[0])
+	t.stream = nil
+}
@@ -0,0 +1,67 @@
+package gl_utils
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+func TestUnpackAlignment(t *testing.T) {
+	tests := []struct {
+		width     int
+		format    uint32
+		alignment int32
+	}{
+		{3, gl.RGB, 1},
+		{4, gl.RGB, 4},
+		{3, gl.RGBA, 4},
+		{2, gl.RED, 1},
+		{8, gl.RED, 4},
+		{1, gl.RG, 1},
+		{2, gl.RG, 4},
+	}
+	for _, test := range tests {
+		rowLength := test.width * bytesPerPixelFormat[test.format]
+		if alignment := unpackAlignment(rowLength); alignment != test.alignment {
+			t.Errorf("%d pixels of format 0x%x: alignment %d, expected %d", test.width, test.format, alignment, test.alignment)
+		}
+	}
+}
+
+func TestNewTextureFromPixelsUnalignedRows(t *testing.T) {
+	r := useRecordingGL(t)
+	// A 3x2 RGB image, each row is 9 bytes long
+	pixels := []byte{
+		255, 0, 0, 0, 255, 0, 0, 0, 255,
+		255, 255, 0, 0, 255, 255, 255, 0, 255,
+	}
+	texture, err := NewTextureFromPixels(3, 2, gl.RGB, pixels)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if texture.Width() != 3 || texture.Height() != 2 {
+		t.Errorf("texture size %dx%d, expected 3x2", texture.Width(), texture.Height())
+	}
+
+	expected := []string{
+		fmt.Sprintf("PixelStorei(%d, 1)", gl.UNPACK_ALIGNMENT),
+		fmt.Sprintf("TexImage2D(%d, 0, %d, 3, 2, 0, %d, %d)", gl.TEXTURE_2D, gl.RGB, gl.RGB, gl.UNSIGNED_BYTE),
+		fmt.Sprintf("PixelStorei(%d, 4)", gl.UNPACK_ALIGNMENT),
+	}
+	var upload []string
+	for _, call := range r.calls {
+		if strings.HasPrefix(call, "PixelStorei(") || strings.HasPrefix(call, "TexImage2D(") {
+			upload = append(upload, call)
+		}
+	}
+	if !reflect.DeepEqual(upload, expected) {
+		t.Errorf("upload calls %v, expected %v", upload, expected)
+	}
+
+	if _, err := NewTextureFromPixels(3, 2, gl.RGB, pixels[:17]); err == nil {
+		t.Error("a buffer too short for the image has been accepted")
+	}
+}
@@ -0,0 +1,288 @@
+package gl_utils
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// LineCap the shape of the ends of a thick line
+type LineCap int
+
+// Line caps supported
+const (
+	// CapButt ends the line exactly at its end points
+	CapButt LineCap = iota
+	// CapSquare extends the line past its end points by half its thickness
+	CapSquare
+	// CapRound ends the line with a semicircle
+	CapRound
+)
+
+const (
+	// Miters longer than this factor times the thickness are clamped, to avoid spikes on very sharp angles
+	thickLineMiterLimit = 4
+	// Number of triangles used for a round cap
+	thickLineRoundCapSegments = 12
+)
+
+// ThickPolylinePrimitive a polyline drawn with a configurable thickness, using mitered joins
+type ThickPolylinePrimitive struct {
+	Primitive2D
+	points    []mgl32.Vec2
+	thickness float32
+	lineCap   LineCap
+}
+
+// NewThickPolylinePrimitive creates a thick line passing through the points, whose coordinates are relative to center
+func NewThickPolylinePrimitive(center mgl32.Vec3, points []mgl32.Vec2, thickness float32) *ThickPolylinePrimitive {
+	// Consecutive duplicated points don't have a direction, skip them
+	filtered := make([]mgl32.Vec2, 0, len(points))
+	for _, p := range points {
+		if len(filtered) == 0 || !p.ApproxEqual(filtered[len(filtered)-1]) {
+			filtered = append(filtered, p)
+		}
+	}
+	if len(filtered) < 2 {
+		fmt.Println("a thick polyline needs at least 2 distinct points")
+		return nil
+	}
+
+	shader := SharedShaderProgram(VertexShaderBase, "", FragmentShaderSolidColor)
+	t := &ThickPolylinePrimitive{
+		Primitive2D: *newPrimitive2D(center, mgl32.Vec2{1, 1}, shader),
+		points:      filtered,
+		thickness:   thickness,
+	}
+	t.arrayMode = gl.TRIANGLES
+	t.rebuildGeometry()
+	return t
+}
+
+// Thickness returns the thickness of the line
+func (t *ThickPolylinePrimitive) Thickness() float32 {
+	return t.thickness
+}
+
+// SetThickness sets the thickness of the line
+func (t *ThickPolylinePrimitive) SetThickness(thickness float32) {
+	t.thickness = thickness
+	t.rebuildGeometry()
+}
+
+// LineCap returns the shape of the ends of the line
+func (t *ThickPolylinePrimitive) LineCap() LineCap {
+	return t.lineCap
+}
+
+// SetLineCap sets the shape of the ends of the line
+func (t *ThickPolylinePrimitive) SetLineCap(lineCap LineCap) {
+	t.lineCap = lineCap
+	t.rebuildGeometry()
+}
+
+func (t *ThickPolylinePrimitive) rebuildGeometry() {
+	halfThickness := t.thickness / 2
+	numPoints := len(t.points)
+	left := make([]mgl32.Vec2, numPoints)
+	right := make([]mgl32.Vec2, numPoints)
+
+	for i, offset := range miterOffsets(t.points, halfThickness) {
+		left[i] = t.points[i].Add(offset)
+		right[i] = t.points[i].Sub(offset)
+	}
+
+	if t.lineCap == CapSquare {
+		startDirection := t.points[0].Sub(t.points[1]).Normalize().Mul(halfThickness)
+		endDirection := t.points[numPoints-1].Sub(t.points[numPoints-2]).Normalize().Mul(halfThickness)
+		left[0] = left[0].Add(startDirection)
+		right[0] = right[0].Add(startDirection)
+		left[numPoints-1] = left[numPoints-1].Add(endDirection)
+		right[numPoints-1] = right[numPoints-1].Add(endDirection)
+	}
+
+	vertices := make([]float32, 0, (numPoints-1)*12)
+	for i := 0; i < numPoints-1; i++ {
+		vertices = appendTriangle(vertices, left[i], right[i], right[i+1])
+		vertices = appendTriangle(vertices, left[i], right[i+1], left[i+1])
+	}
+
+	if t.lineCap == CapRound {
+		vertices = appendRoundCap(vertices, t.points[0], t.points[0].Sub(t.points[1]), halfThickness)
+		vertices = appendRoundCap(vertices, t.points[numPoints-1], t.points[numPoints-1].Sub(t.points[numPoints-2]), halfThickness)
+	}
+
+	t.SetVertices(vertices)
+}
+
+// miterOffsets returns, for each point of a line, the offset from the point to the left edge of the line with the
+// specified half thickness. At the joins the offset follows the miter, clamped on very sharp angles
+func miterOffsets(points []mgl32.Vec2, halfThickness float32) []mgl32.Vec2 {
+	numPoints := len(points)
+	offsets := make([]mgl32.Vec2, numPoints)
+	for i, p := range points {
+		switch i {
+		case 0:
+			offsets[i] = segmentNormal(p, points[1]).Mul(halfThickness)
+		case numPoints - 1:
+			offsets[i] = segmentNormal(points[i-1], p).Mul(halfThickness)
+		default:
+			n0 := segmentNormal(points[i-1], p)
+			n1 := segmentNormal(p, points[i+1])
+			miter := n0.Add(n1)
+			if miter.Len() < 1e-6 {
+				// The line folds back on itself
+				miter = n0
+			}
+			miter = miter.Normalize()
+			length := halfThickness / miter.Dot(n1)
+			if length > halfThickness*thickLineMiterLimit {
+				length = halfThickness * thickLineMiterLimit
+			}
+			offsets[i] = miter.Mul(length)
+		}
+	}
+	return offsets
+}
+
+// segmentNormal returns the unit vector perpendicular to the segment a-b
+func segmentNormal(a, b mgl32.Vec2) mgl32.Vec2 {
+	direction := b.Sub(a).Normalize()
+	return mgl32.Vec2{-direction.Y(), direction.X()}
+}
+
+func appendTriangle(vertices []float32, a, b, c mgl32.Vec2) []float32 {
+	return append(vertices, a.X(), a.Y(), b.X(), b.Y(), c.X(), c.Y())
+}
+
+// appendRoundCap adds a semicircle centered on center and bulging in the specified direction
+func appendRoundCap(vertices []float32, center mgl32.Vec2, direction mgl32.Vec2, radius float32) []float32 {
+	startAngle := math.Atan2(float64(direction.Y()), float64(direction.X())) - math.Pi/2
+	step := math.Pi / thickLineRoundCapSegments
+	previous := center.Add(mgl32.Vec2{float32(math.Cos(startAngle)), float32(math.Sin(startAngle))}.Mul(radius))
+	for i := 1; i <= thickLineRoundCapSegments; i++ {
+		angle := startAngle + step*float64(i)
+		next := center.Add(mgl32.Vec2{float32(math.Cos(angle)), float32(math.Sin(angle))}.Mul(radius))
+		vertices = appendTriangle(vertices, center, previous, next)
+		previous = next
+	}
+	return vertices
+}
+
+// LineStripAdjacencyVertices returns the vertices of the points for gl.LINE_STRIP_ADJACENCY, where each segment is
+// drawn knowing the points before and after it. A phantom point is added at each end, mirroring the second (and the
+// second to last) point, so that the ends of the line are square. Consecutive duplicated points are skipped
+func LineStripAdjacencyVertices(points []mgl32.Vec2) []float32 {
+	filtered := make([]mgl32.Vec2, 0, len(points))
+	for _, p := range points {
+		if len(filtered) == 0 || !p.ApproxEqual(filtered[len(filtered)-1]) {
+			filtered = append(filtered, p)
+		}
+	}
+	if len(filtered) < 2 {
+		return nil
+	}
+	last := len(filtered) - 1
+	start := filtered[0].Mul(2).Sub(filtered[1])
+	end := filtered[last].Mul(2).Sub(filtered[last-1])
+
+	vertices := make([]float32, 0, (len(filtered)+2)*2)
+	vertices = append(vertices, start.X(), start.Y())
+	for _, p := range filtered {
+		vertices = append(vertices, p.X(), p.Y())
+	}
+	return append(vertices, end.X(), end.Y())
+}
+
+// NewGPUThickPolylinePrimitive creates a thick line passing through the points, whose coordinates are relative to
+// center. Unlike ThickPolylinePrimitive the line is expanded into triangles by a geometry shader
+// (GeometryShaderThickLine), only the points are uploaded. The thickness is the 'thickness' uniform of the material
+func NewGPUThickPolylinePrimitive(center mgl32.Vec3, points []mgl32.Vec2, thickness float32) *Primitive2D {
+	vertices := LineStripAdjacencyVertices(points)
+	if vertices == nil {
+		fmt.Println("a thick polyline needs at least 2 distinct points")
+		return nil
+	}
+	p := newPrimitive2D(center, mgl32.Vec2{1, 1}, nil)
+	material := NewMaterial(SharedShaderProgram(VertexShaderThickLine, GeometryShaderThickLine, FragmentShaderSolidColor))
+	material.SetUniform("thickness", thickness)
+	p.SetMaterial(material)
+	p.arrayMode = gl.LINE_STRIP_ADJACENCY
+	p.SetVertices(vertices)
+	return p
+}
+
+const (
+	// VertexShaderThickLine passes the world position of the vertices to GeometryShaderThickLine, which applies the
+	// projection
+	VertexShaderThickLine = `
+        #version 410 core
+
+        uniform mat4 model;
+
+        layout(location=0) in vec2 vertex;
+
+        void main() {
+            gl_Position = model * vec4(vertex, 0, 1);
+        }
+        ` + "\x00"
+
+	// GeometryShaderThickLine expands each segment of a gl.LINE_STRIP_ADJACENCY primitive into a quad as wide as the
+	// 'thickness' uniform (in world units), mitering the joins with the previous and the next segment. Like the CPU
+	// version, miters are clamped to 4 times the thickness. U goes from 0 to 1 along the segment, V across it
+	GeometryShaderThickLine = `
+        #version 410 core
+
+        layout(lines_adjacency) in;
+        layout(triangle_strip, max_vertices = 4) out;
+
+        uniform mat4 projection;
+        uniform float thickness;
+
+        out vec2 uv_out;
+
+        vec2 normal_of(vec2 a, vec2 b) {
+            vec2 direction = normalize(b - a);
+            return vec2(-direction.y, direction.x);
+        }
+
+        // Offset from a join to the left edge of the line
+        vec2 miter(vec2 normal, vec2 other_normal) {
+            vec2 direction = normal + other_normal;
+            if (length(direction) < 1e-6) {
+                // The line folds back on itself
+                direction = normal;
+            }
+            direction = normalize(direction);
+            float half_thickness = thickness * 0.5;
+            float miter_length = min(half_thickness / dot(direction, normal), half_thickness * 4.0);
+            return direction * miter_length;
+        }
+
+        void emit(vec2 position, float z, vec2 uv) {
+            gl_Position = projection * vec4(position, z, 1);
+            uv_out = uv;
+            EmitVertex();
+        }
+
+        void main() {
+            vec2 previous = gl_in[0].gl_Position.xy;
+            vec2 start = gl_in[1].gl_Position.xy;
+            vec2 end = gl_in[2].gl_Position.xy;
+            vec2 next = gl_in[3].gl_Position.xy;
+            float z = gl_in[1].gl_Position.z;
+
+            vec2 normal = normal_of(start, end);
+            vec2 start_offset = miter(normal, normal_of(previous, start));
+            vec2 end_offset = miter(normal, normal_of(end, next));
+
+            emit(start + start_offset, z, vec2(0, 0));
+            emit(start - start_offset, z, vec2(0, 1));
+            emit(end + end_offset, z, vec2(1, 0));
+            emit(end - end_offset, z, vec2(1, 1));
+            EndPrimitive();
+        }
+        ` + "\x00"
+)
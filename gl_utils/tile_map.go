@@ -0,0 +1,125 @@
+package gl_utils
+
+import (
+	"fmt"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// TileMapPrimitive a grid of tiles taken from a sprite sheet and drawn with a single draw call
+type TileMapPrimitive struct {
+	Primitive2D
+	sheet      *SpriteSheet
+	tiles      [][]int
+	tileWidth  int
+	tileHeight int
+	// slots maps the position of a tile in the grid to its position in the vertex buffer, -1 for empty tiles
+	slots [][]int
+}
+
+// NewTileMapPrimitive creates a primitive covering the whole grid of tiles. The tiles are indexed as tiles[y][x],
+// indices < 0 mark empty tiles which don't generate any geometry. The grid is copied, use SetTile to change it.
+// It returns nil if the sheet is missing or the tile size isn't positive
+func NewTileMapPrimitive(sheet *SpriteSheet, tiles [][]int, tileWidth, tileHeight int) *TileMapPrimitive {
+	if sheet == nil {
+		fmt.Println("Error: cannot create a tile map without a sprite sheet")
+		return nil
+	}
+	if tileWidth <= 0 || tileHeight <= 0 {
+		fmt.Printf("Error: cannot create a tile map with tiles of size %dx%d\n", tileWidth, tileHeight)
+		return nil
+	}
+	shader := SharedShaderProgram(VertexShaderBase, "", FragmentShaderTexture)
+	t := &TileMapPrimitive{
+		Primitive2D: *newPrimitive2D(mgl32.Vec3{}, mgl32.Vec2{1, 1}, shader),
+		sheet:       sheet,
+		tiles:       copyTiles(tiles),
+		tileWidth:   tileWidth,
+		tileHeight:  tileHeight,
+	}
+	t.texture = sheet.Texture()
+	t.arrayMode = gl.TRIANGLES
+	t.rebuildGeometry()
+	return t
+}
+
+// Tile returns the index of the tile at the grid position x,y, -1 if the position is outside of the grid
+func (t *TileMapPrimitive) Tile(x, y int) int {
+	if !t.insideGrid(x, y) {
+		return -1
+	}
+	return t.tiles[y][x]
+}
+
+// SetTile changes a single tile. When the tile is already part of the mesh only its UVs are updated in place,
+// otherwise (a tile added or removed) the whole geometry is rebuilt
+func (t *TileMapPrimitive) SetTile(x, y, index int) {
+	if !t.insideGrid(x, y) {
+		fmt.Printf("Error: the tile %d,%d is outside of the map\n", x, y)
+		return
+	}
+	t.tiles[y][x] = index
+	slot := t.slots[y][x]
+	if slot < 0 || index < 0 {
+		t.rebuildGeometry()
+		return
+	}
+
+	uvCoords := t.tileUVCoords(index)
+	glc.BindBuffer(gl.ARRAY_BUFFER, t.vboUVCoords)
+	glc.BufferSubData(gl.ARRAY_BUFFER, slot*len(uvCoords)*Float32Size, len(uvCoords)*Float32Size, gl.Ptr(uvCoords))
+	countBufferUpload()
+	glc.BindBuffer(gl.ARRAY_BUFFER, 0)
+}
+
+// insideGrid returns whether x,y is a position of the grid of tiles
+func (t *TileMapPrimitive) insideGrid(x, y int) bool {
+	return y >= 0 && y < len(t.tiles) && x >= 0 && x < len(t.tiles[y])
+}
+
+func (t *TileMapPrimitive) rebuildGeometry() {
+	vertices := make([]float32, 0)
+	uvCoords := make([]float32, 0)
+	t.slots = make([][]int, len(t.tiles))
+	numSlots := 0
+	for y, row := range t.tiles {
+		t.slots[y] = make([]int, len(row))
+		for x, index := range row {
+			if index < 0 {
+				t.slots[y][x] = -1
+				continue
+			}
+			t.slots[y][x] = numSlots
+			numSlots++
+
+			x0 := float32(x * t.tileWidth)
+			y0 := float32(y * t.tileHeight)
+			x1 := x0 + float32(t.tileWidth)
+			y1 := y0 + float32(t.tileHeight)
+			vertices = append(vertices, x0, y0, x0, y1, x1, y1, x0, y0, x1, y1, x1, y0)
+			uvCoords = append(uvCoords, t.tileUVCoords(index)...)
+		}
+	}
+	if numSlots == 0 {
+		// Nothing to upload, an empty buffer can't be passed to OpenGL
+		t.arraySize = 0
+		return
+	}
+	t.SetVertices(vertices)
+	t.SetUVCoords(uvCoords)
+}
+
+func (t *TileMapPrimitive) tileUVCoords(index int) []float32 {
+	u0, v0, u1, v1 := t.sheet.Frame(index)
+	return []float32{u0, v0, u0, v1, u1, v1, u0, v0, u1, v1, u1, v0}
+}
+
+// copyTiles returns a copy of the grid of tiles
+func copyTiles(tiles [][]int) [][]int {
+	tilesCopy := make([][]int, len(tiles))
+	for y, row := range tiles {
+		tilesCopy[y] = append([]int(nil), row...)
+	}
+	return tilesCopy
+}
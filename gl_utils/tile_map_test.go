@@ -0,0 +1,37 @@
+package gl_utils
+
+import (
+	"testing"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+func TestNewTileMapPrimitiveValidatesInput(t *testing.T) {
+	useRecordingGL(t)
+	texture, err := NewTextureFromPixels(4, 4, gl.RGBA, make([]uint8, 64))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sheet := NewSpriteSheet(texture, 2, 2)
+	tiles := [][]int{{0, 1}, {2, -1}}
+	tests := []struct {
+		name                  string
+		sheet                 *SpriteSheet
+		tileWidth, tileHeight int
+		valid                 bool
+	}{
+		{"missing sheet", NewSpriteSheet(texture, 0, 2), 16, 16, false},
+		{"zero tile width", sheet, 0, 16, false},
+		{"negative tile height", sheet, 16, -1, false},
+		{"valid", sheet, 16, 16, true},
+	}
+	for _, test := range tests {
+		tileMap := NewTileMapPrimitive(test.sheet, tiles, test.tileWidth, test.tileHeight)
+		if test.valid && tileMap == nil {
+			t.Errorf("%s: unexpected nil tile map", test.name)
+		}
+		if !test.valid && tileMap != nil {
+			t.Errorf("%s: expected nil, got a tile map", test.name)
+		}
+	}
+}
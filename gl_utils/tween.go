@@ -0,0 +1,269 @@
+package gl_utils
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// EaseFunc maps the normalized time of a tween (0..1) to the normalized progress of the value
+type EaseFunc func(t float32) float32
+
+// EaseLinear progresses at constant speed
+func EaseLinear(t float32) float32 { return t }
+
+// EaseInQuad accelerates from zero velocity
+func EaseInQuad(t float32) float32 { return t * t }
+
+// EaseOutQuad decelerates to zero velocity
+func EaseOutQuad(t float32) float32 { return 1 - (1-t)*(1-t) }
+
+// EaseInOutQuad accelerates until halfway, then decelerates
+func EaseInOutQuad(t float32) float32 {
+	if t < 0.5 {
+		return 2 * t * t
+	}
+	return 1 - (-2*t+2)*(-2*t+2)/2
+}
+
+// EaseInCubic accelerates from zero velocity
+func EaseInCubic(t float32) float32 { return t * t * t }
+
+// EaseOutCubic decelerates to zero velocity
+func EaseOutCubic(t float32) float32 { return 1 - (1-t)*(1-t)*(1-t) }
+
+// EaseInOutCubic accelerates until halfway, then decelerates
+func EaseInOutCubic(t float32) float32 {
+	if t < 0.5 {
+		return 4 * t * t * t
+	}
+	f := -2*t + 2
+	return 1 - f*f*f/2
+}
+
+// EaseInSine accelerates following a sinusoidal curve
+func EaseInSine(t float32) float32 {
+	return 1 - float32(math.Cos(float64(t)*math.Pi/2))
+}
+
+// EaseOutSine decelerates following a sinusoidal curve
+func EaseOutSine(t float32) float32 {
+	return float32(math.Sin(float64(t) * math.Pi / 2))
+}
+
+// EaseInOutSine accelerates and decelerates following a sinusoidal curve
+func EaseInOutSine(t float32) float32 {
+	return -(float32(math.Cos(math.Pi*float64(t))) - 1) / 2
+}
+
+// EaseInElastic oscillates around the start value before moving to the end value
+func EaseInElastic(t float32) float32 {
+	if t <= 0 || t >= 1 {
+		return t
+	}
+	c4 := 2 * math.Pi / 3
+	x := float64(t)
+	return float32(-math.Pow(2, 10*x-10) * math.Sin((10*x-10.75)*c4))
+}
+
+// EaseOutElastic overshoots the end value and oscillates around it
+func EaseOutElastic(t float32) float32 {
+	if t <= 0 || t >= 1 {
+		return t
+	}
+	c4 := 2 * math.Pi / 3
+	x := float64(t)
+	return float32(math.Pow(2, -10*x)*math.Sin((10*x-0.75)*c4) + 1)
+}
+
+// EaseInOutElastic oscillates around both the start and the end values
+func EaseInOutElastic(t float32) float32 {
+	if t <= 0 || t >= 1 {
+		return t
+	}
+	c5 := 2 * math.Pi / 4.5
+	x := float64(t)
+	if x < 0.5 {
+		return float32(-(math.Pow(2, 20*x-10) * math.Sin((20*x-11.125)*c5)) / 2)
+	}
+	return float32(math.Pow(2, -20*x+10)*math.Sin((20*x-11.125)*c5)/2 + 1)
+}
+
+// EaseInBounce bounces on the start value before moving to the end value
+func EaseInBounce(t float32) float32 {
+	return 1 - EaseOutBounce(1-t)
+}
+
+// EaseOutBounce bounces on the end value
+func EaseOutBounce(t float32) float32 {
+	const n1 = 7.5625
+	const d1 = 2.75
+	switch {
+	case t < 1/d1:
+		return n1 * t * t
+	case t < 2/d1:
+		t -= 1.5 / d1
+		return n1*t*t + 0.75
+	case t < 2.5/d1:
+		t -= 2.25 / d1
+		return n1*t*t + 0.9375
+	default:
+		t -= 2.625 / d1
+		return n1*t*t + 0.984375
+	}
+}
+
+// EaseInOutBounce bounces on both the start and the end values
+func EaseInOutBounce(t float32) float32 {
+	if t < 0.5 {
+		return (1 - EaseOutBounce(1-2*t)) / 2
+	}
+	return (1 + EaseOutBounce(2*t-1)) / 2
+}
+
+// Tween interpolates a value between two extremes over a period of time
+type Tween struct {
+	from     float32
+	to       float32
+	duration float32
+	elapsed  float32
+	ease     EaseFunc
+	value    float32
+	finished bool
+	// OnUpdate is called with the new value every time the tween advances
+	OnUpdate func(value float32)
+	// OnComplete is called once, when the tween reaches the end value
+	OnComplete func()
+}
+
+// NewTween creates a tween going from 'from' to 'to' in 'duration' seconds. A nil ease defaults to EaseLinear
+func NewTween(from, to, duration float32, ease EaseFunc) *Tween {
+	if ease == nil {
+		ease = EaseLinear
+	}
+	return &Tween{
+		from:     from,
+		to:       to,
+		duration: duration,
+		ease:     ease,
+		value:    from,
+	}
+}
+
+// Value returns the current value of the tween
+func (t *Tween) Value() float32 {
+	return t.value
+}
+
+// Finished returns true once the tween reached the end value
+func (t *Tween) Finished() bool {
+	return t.finished
+}
+
+// Update advances the tween by dt seconds
+func (t *Tween) Update(dt float32) {
+	if t.finished {
+		return
+	}
+	t.elapsed += dt
+	progress := float32(1)
+	if t.duration > 0 {
+		progress = mgl32.Clamp(t.elapsed/t.duration, 0, 1)
+	}
+	t.value = t.from + (t.to-t.from)*t.ease(progress)
+	if progress >= 1 {
+		t.value = t.to
+		t.finished = true
+	}
+	if t.OnUpdate != nil {
+		t.OnUpdate(t.value)
+	}
+	if t.finished && t.OnComplete != nil {
+		t.OnComplete()
+	}
+}
+
+// Tweener keeps a collection of running tweens, finished tweens are removed automatically
+type Tweener struct {
+	tweens []*Tween
+}
+
+// NewTweener creates an empty tweener
+func NewTweener() *Tweener {
+	return &Tweener{}
+}
+
+// To starts a new tween and returns it
+func (tw *Tweener) To(from, to, duration float32, ease EaseFunc) *Tween {
+	t := NewTween(from, to, duration, ease)
+	tw.tweens = append(tw.tweens, t)
+	return t
+}
+
+// Update advances all the running tweens by dt seconds
+func (tw *Tweener) Update(dt float32) {
+	// Tweens started by the callbacks are appended to the new list
+	current := tw.tweens
+	tw.tweens = make([]*Tween, 0, len(current))
+	for _, t := range current {
+		t.Update(dt)
+		if !t.Finished() {
+			tw.tweens = append(tw.tweens, t)
+		}
+	}
+}
+
+// Len returns the number of tweens still running
+func (tw *Tweener) Len() int {
+	return len(tw.tweens)
+}
+
+// Clear stops all the running tweens
+func (tw *Tweener) Clear() {
+	tw.tweens = nil
+}
+
+// Position moves a primitive from its current position to 'to'
+func (tw *Tweener) Position(p *Primitive2D, to mgl32.Vec3, duration float32, ease EaseFunc) *Tween {
+	from := p.Position()
+	t := tw.To(0, 1, duration, ease)
+	t.OnUpdate = func(v float32) {
+		p.SetPosition(from.Add(to.Sub(from).Mul(v)))
+	}
+	return t
+}
+
+// Angle rotates a primitive from its current angle to 'to' (in radians)
+func (tw *Tweener) Angle(p *Primitive2D, to float32, duration float32, ease EaseFunc) *Tween {
+	t := tw.To(p.Angle(), to, duration, ease)
+	t.OnUpdate = p.SetAngle
+	return t
+}
+
+// Scale scales a primitive from its current scaling factor to 'to'
+func (tw *Tweener) Scale(p *Primitive2D, to mgl32.Vec2, duration float32, ease EaseFunc) *Tween {
+	from := p.scale
+	t := tw.To(0, 1, duration, ease)
+	t.OnUpdate = func(v float32) {
+		p.SetScale(from.Add(to.Sub(from).Mul(v)))
+	}
+	return t
+}
+
+// Color changes the color of a primitive from its current color to 'to'. A primitive using a shared color (see
+// SetColorRef) keeps using it: the shared color is changed, so all the primitives sharing it are animated
+func (tw *Tweener) Color(p *Primitive2D, to Color, duration float32, ease EaseFunc) *Tween {
+	from := mgl32.Vec4(*p.drawColor())
+	target := mgl32.Vec4(to)
+	shared := p.ColorRef()
+	t := tw.To(0, 1, duration, ease)
+	t.OnUpdate = func(v float32) {
+		color := Color(from.Add(target.Sub(from).Mul(v)))
+		if shared != nil {
+			*shared = color
+		} else {
+			p.SetColor(color)
+		}
+	}
+	return t
+}
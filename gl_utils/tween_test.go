@@ -0,0 +1,39 @@
+package gl_utils
+
+import (
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestTweenColorKeepsSharedColor(t *testing.T) {
+	useRecordingGL(t)
+	shared := Color{0, 0, 0, 1}
+	p := NewRectPrimitive(mgl32.Vec3{}, mgl32.Vec2{1, 1}, true)
+	p.SetColorRef(&shared)
+	tweener := NewTweener()
+	tweener.Color(p, Color{1, 1, 1, 1}, 1, EaseLinear)
+
+	tweener.Update(0.5)
+
+	if p.ColorRef() != &shared {
+		t.Fatal("the tween replaced the shared color of the primitive")
+	}
+	if shared != (Color{0.5, 0.5, 0.5, 1}) {
+		t.Errorf("the shared color is %v halfway, expected 0.5 grey", shared)
+	}
+}
+
+func TestTweenColor(t *testing.T) {
+	useRecordingGL(t)
+	p := NewRectPrimitive(mgl32.Vec3{}, mgl32.Vec2{1, 1}, true)
+	p.SetColor(Color{1, 0, 0, 1})
+	tweener := NewTweener()
+	tweener.Color(p, Color{0, 0, 1, 1}, 2, EaseLinear)
+
+	tweener.Update(2)
+
+	if p.Color() != (Color{0, 0, 1, 1}) || tweener.Len() != 0 {
+		t.Errorf("color %v at the end, %d tweens still running", p.Color(), tweener.Len())
+	}
+}
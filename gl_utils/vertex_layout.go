@@ -0,0 +1,81 @@
+package gl_utils
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// VertexAttribute describes where an attribute is found inside a vertex buffer
+type VertexAttribute struct {
+	// Location of the attribute in the shader (layout(location=N))
+	Location uint32
+	// Components is the number of values of the attribute (1 to 4)
+	Components int32
+	// Type of each value, usually gl.FLOAT
+	Type uint32
+	// Normalized maps integer values to the 0-1 (or -1-1) range
+	Normalized bool
+	// Stride is the distance in bytes between two consecutive vertices, 0 if the attribute is tightly packed
+	Stride int32
+	// Offset is the position in bytes of the attribute of the first vertex
+	Offset int
+}
+
+// VertexLayout the attributes contained in a vertex buffer. Interleaved attributes share the same stride
+type VertexLayout []VertexAttribute
+
+// Stride returns the size in bytes of a vertex, taken from the first attribute of the layout
+func (l VertexLayout) Stride() int32 {
+	if len(l) == 0 {
+		return 0
+	}
+	if l[0].Stride != 0 {
+		return l[0].Stride
+	}
+	return l[0].Components * Float32Size
+}
+
+// SetVertexData uploads a vertex buffer described by a custom layout and sets the number of vertices drawn. It can
+// replace or extend the attributes uploaded by the other setters, for shaders needing inputs the primitives don't
+// provide (e.g. tangents). The vertices set with SetVertices are forgotten, since they don't describe the geometry
+// drawn anymore
+func (p *Primitive) SetVertexData(data []float32, layout VertexLayout) error {
+	if len(layout) == 0 {
+		return errors.New("the vertex layout is empty")
+	}
+	stride := layout.Stride()
+	if stride <= 0 {
+		return errors.New("the stride of the vertex layout must be > 0")
+	}
+	if len(data) == 0 {
+		return errors.New("no vertex data")
+	}
+	for _, attribute := range layout {
+		if attribute.Components < 1 || attribute.Components > 4 {
+			return fmt.Errorf("attribute %d: components must be between 1 and 4", attribute.Location)
+		}
+	}
+
+	p.bindVertexArray()
+	if p.vboData == 0 {
+		glc.GenBuffers(1, &p.vboData)
+	}
+	glc.BindBuffer(gl.ARRAY_BUFFER, p.vboData)
+	glc.BufferData(gl.ARRAY_BUFFER, len(data)*Float32Size, gl.Ptr(data), gl.STATIC_DRAW)
+	countBufferUpload()
+	for _, attribute := range layout {
+		glc.EnableVertexAttribArray(attribute.Location)
+		glc.VertexAttribPointer(
+			attribute.Location, attribute.Components, attribute.Type, attribute.Normalized,
+			attribute.Stride, gl.PtrOffset(attribute.Offset),
+		)
+	}
+	glc.BindVertexArray(0)
+
+	p.arraySize = int32(len(data)*Float32Size) / stride
+	p.vertices = nil
+	p.verticesCapacity = 0
+	return nil
+}